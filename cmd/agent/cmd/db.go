@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// dbCmd represents the db command
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Local database maintenance commands",
+	Long:  `Local database maintenance commands.`,
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
+	"github.com/spf13/cobra"
+)
+
+var dbMigrateOpts struct {
+	path string
+}
+
+// dbMigrateCmd represents the migrate-timestamps command
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate-timestamps",
+	Short: "Convert samples stored with second timestamps into milliseconds",
+	Long: `Convert samples stored with second timestamps into milliseconds.
+
+Older versions of the agent wrote timestamps in seconds while the TSDB
+expects milliseconds. This command rewrites the whole database in the
+correct unit and keeps the original data in a backup directory next
+to it. Stop any running agent before executing it.
+
+Databases written by those versions are usually in the legacy '.db/tsdb.db'
+directory, relative to where the agent ran, so --path should point to it
+and the migrated directory be moved to the configured storage path
+afterwards.`,
+	Example: `  agent db migrate-timestamps --path .db/tsdb.db
+  mv .db/tsdb.db /var/lib/rpi-monitor/tsdb`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := dbMigrateOpts.path
+		if path == "" {
+			path = persistence.Path()
+		}
+		converted, err := persistence.MigrateTimestamps(cmd.Context(), path)
+		if err != nil {
+			return err
+		}
+		if converted == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "Nothing to migrate in %s\n", path)
+			return nil
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Converted %d samples in %s\n", converted, path)
+		return nil
+	},
+}
+
+func init() {
+	dbCmd.AddCommand(dbMigrateCmd)
+
+	dbMigrateCmd.Flags().StringVar(&dbMigrateOpts.path, "path", "", "Database directory to migrate (default is the configured storage path)")
+}
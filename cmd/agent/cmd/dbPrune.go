@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
+	"github.com/eldius/rpi-system-monitor/internal/timeutil"
+	"github.com/spf13/cobra"
+)
+
+var dbPruneOpts struct {
+	olderThan string
+}
+
+// dbPruneCmd represents the prune command
+var dbPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete samples older than a given age",
+	Long: `Delete samples, and processes snapshots, older than a given age.
+
+Samples are also removed automatically according to the
+'monitor.storage.retention' and 'monitor.storage.max_size' settings,
+this command is meant for manual cleanups. The processes snapshots of
+the current UTC day are only removed once the day is over.`,
+	Example: `  agent db prune --older-than 30d`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		age, err := timeutil.ParseDuration(dbPruneOpts.olderThan)
+		if err != nil {
+			return fmt.Errorf("parsing --older-than: %w", err)
+		}
+		before := time.Now().Add(-age)
+
+		store, err := persistence.Default()
+		if err != nil {
+			return err
+		}
+		if err := store.Prune(cmd.Context(), before); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed samples taken before %s from %s\n", before.Format(time.DateTime), persistence.Path())
+
+		processes, err := persistence.DefaultProcesses()
+		if err != nil {
+			return err
+		}
+		if err := processes.Prune(before); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed processes snapshots taken before %s from %s\n", before.Format(time.DateTime), persistence.ProcessesPath())
+		return nil
+	},
+}
+
+func init() {
+	dbCmd.AddCommand(dbPruneCmd)
+
+	dbPruneCmd.Flags().StringVar(&dbPruneOpts.olderThan, "older-than", "", "Age of the samples to delete (e.g. 12h, 30d)")
+	_ = dbPruneCmd.MarkFlagRequired("older-than")
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/adapter"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/eldius/rpi-system-monitor/internal/output"
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
+	"github.com/eldius/rpi-system-monitor/internal/timeutil"
+	"github.com/spf13/cobra"
+)
+
+var eventsOpts struct {
+	since  string
+	until  string
+	last   string
+	all    bool
+	output string
+}
+
+// eventsCmd represents the events command
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List the unexpected reboots recorded by the agent",
+	Long: `List the unexpected reboots recorded by the agent.
+
+A reboot is detected on the first measurement after the system booted
+and is unexpected when the agent didn't record a shutdown before it,
+as after a power loss, a kernel panic or a watchdog reset. Shutdowns
+are only recorded by the 'run' and 'serve' commands, on systems using
+systemd, so every reboot is unexpected without it.
+
+Times accept RFC 3339 dates, "2006-01-02 15:04:05" (local time), unix
+timestamps or values relative to now such as "now-1h" or "-30m".`,
+	Example: `  agent events
+  agent events --last 30d --all`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		_, err := output.ParseFormat(eventsOpts.output)
+		return err
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := output.ParseFormat(eventsOpts.output)
+		from, to, err := eventsRange(time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using database: %s\n", persistence.Path())
+		events, err := adapter.Events(cmd.Context(), from, to)
+		if err != nil {
+			return err
+		}
+		if !eventsOpts.all {
+			events = unexpectedReboots(events)
+		}
+		return output.WriteEvents(cmd.OutOrStdout(), format, events)
+	},
+}
+
+// eventsRange parses the time range of the events from the command
+// flags, zero values mean unbounded.
+func eventsRange(now time.Time) (from, to time.Time, err error) {
+	if eventsOpts.last != "" && eventsOpts.since != "" {
+		return from, to, errors.New("--last and --since can't be used together")
+	}
+	if eventsOpts.until != "" {
+		if to, err = timeutil.ParseTime(eventsOpts.until, now); err != nil {
+			return from, to, fmt.Errorf("parsing --until: %w", err)
+		}
+	}
+	if eventsOpts.since != "" {
+		if from, err = timeutil.ParseTime(eventsOpts.since, now); err != nil {
+			return from, to, fmt.Errorf("parsing --since: %w", err)
+		}
+	}
+	if eventsOpts.last != "" {
+		last, err := timeutil.ParseDuration(eventsOpts.last)
+		if err != nil {
+			return from, to, fmt.Errorf("parsing --last: %w", err)
+		}
+		end := to
+		if end.IsZero() {
+			end = now
+		}
+		from = end.Add(-last)
+	}
+	return from, to, nil
+}
+
+func unexpectedReboots(events []model.EventResult) []model.EventResult {
+	var result []model.EventResult
+	for _, e := range events {
+		if e.Type == model.EventReboot && e.Unexpected {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+
+	eventsCmd.Flags().StringVar(&eventsOpts.since, "since", "", "List events that happened after this time")
+	eventsCmd.Flags().StringVar(&eventsOpts.until, "until", "", "List events that happened before this time")
+	eventsCmd.Flags().StringVar(&eventsOpts.last, "last", "", "List events of the last period (e.g. 7d, 30d)")
+	eventsCmd.Flags().BoolVar(&eventsOpts.all, "all", false, "List every reboot and shutdown, not only the unexpected reboots")
+	eventsCmd.Flags().StringVarP(&eventsOpts.output, "output", "o", string(output.FormatTable), fmt.Sprintf("Output format, one of %v", output.Formats))
+}
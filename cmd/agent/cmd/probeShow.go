@@ -1,52 +1,143 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/eldius/rpi-system-monitor/internal/adapter"
+	"github.com/eldius/rpi-system-monitor/internal/output"
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
+	"github.com/eldius/rpi-system-monitor/internal/timeutil"
 	"github.com/spf13/cobra"
 )
 
+var probeShowOpts struct {
+	since   string
+	until   string
+	last    string
+	step    string
+	metrics []string
+
+	processes bool
+	at        string
+}
+
 // probeShowCmd represents the show command
 var probeShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Display time series data for probe measurements",
-	Long:  `Display time series data for probe measurements.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("show called")
-		values, err := adapter.Get(cmd.Context())
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-		fmt.Println("######################################################")
-		fmt.Println("")
-		fmt.Println("Monitoring time series:")
-		for _, result := range values {
-			fmt.Println("---")
-			fmt.Printf("- Timestamp:        %s\n", result.Timestamp.Format("2006-01-02 15:04:05"))
-			fmt.Printf("  CPU Count:        %d\n", result.CPU.CPUCount)
-			fmt.Printf("  CPU Usage:        %.2f%%\n", result.CPU.CPUUsage)
-			fmt.Printf("  Memory Usage:     %.2d/%d (%.2f%%)\n", result.Memory.UsedMemory, result.Memory.TotalMemory, result.Memory.MemoryUsagePercentage)
-			fmt.Printf("  Memory Usage (h): %s/%s (%.2f%%)\n", result.Memory.UsedMemoryStr(), result.Memory.TotalMemoryStr(), result.Memory.MemoryUsagePercentage)
-			fmt.Printf("  Temperature:      %.2f°C\n", result.Temp.Temperature)
-		}
-		fmt.Println("######################################################")
-		fmt.Println("")
+	Long: `Display time series data for probe measurements.
 
+Times accept RFC 3339 dates, "2006-01-02 15:04:05" (local time), unix
+timestamps or values relative to now such as "now-1h" or "-30m".
+
+Reboots and shutdowns in the range are marked between the measurements
+in the table and csv outputs.
+
+With --processes the processes using the most CPU and memory in the
+snapshot taken closest to --at (now by default) are shown instead.`,
+	Example: `  agent probe show --last 1h
+  agent probe show --since "2025-01-01 08:00" --until "2025-01-01 12:00" --step 5m
+  agent probe show --last 24h --metric temperature --metric cpu_usage
+  agent probe show --processes --at "2025-01-01 08:30"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.ParseFormat(probeOpts.output)
+		if err != nil {
+			return err
+		}
+		if probeShowOpts.processes {
+			return showProcesses(cmd, format, time.Now())
+		}
+		if probeShowOpts.at != "" {
+			return errors.New("--at can only be used with --processes")
+		}
+		f, err := probeShowFilter(time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using database: %s\n", persistence.Path())
+		values, err := adapter.Get(cmd.Context(), f)
+		if err != nil {
+			return err
+		}
+		events, err := adapter.Events(cmd.Context(), f.From, f.To)
+		if err != nil {
+			return err
+		}
+		return output.Write(cmd.OutOrStdout(), format, values, f.Metrics, events)
 	},
 }
 
-func init() {
-	probeCmd.AddCommand(probeShowCmd)
+// showProcesses prints the processes snapshot taken closest to --at.
+func showProcesses(cmd *cobra.Command, format output.Format, now time.Time) error {
+	if probeShowOpts.since != "" || probeShowOpts.until != "" || probeShowOpts.last != "" || probeShowOpts.step != "" || len(probeShowOpts.metrics) > 0 {
+		return errors.New("--processes only accepts --at to select the snapshot")
+	}
+	at := now
+	if probeShowOpts.at != "" {
+		var err error
+		if at, err = timeutil.ParseTime(probeShowOpts.at, now); err != nil {
+			return fmt.Errorf("parsing --at: %w", err)
+		}
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Using processes store: %s\n", persistence.ProcessesPath())
+	snapshot, err := adapter.ProcessesAt(cmd.Context(), at)
+	if err != nil {
+		return err
+	}
+	return output.WriteProcesses(cmd.OutOrStdout(), format, snapshot)
+}
+
+// probeShowFilter builds the persistence filter from the command flags.
+func probeShowFilter(now time.Time) (persistence.Filter, error) {
+	f := persistence.Filter{
+		Metrics: probeShowOpts.metrics,
+	}
+
+	if probeShowOpts.last != "" && probeShowOpts.since != "" {
+		return f, errors.New("--last and --since can't be used together")
+	}
+
+	var err error
+	if probeShowOpts.until != "" {
+		if f.To, err = timeutil.ParseTime(probeShowOpts.until, now); err != nil {
+			return f, fmt.Errorf("parsing --until: %w", err)
+		}
+	}
+	if probeShowOpts.since != "" {
+		if f.From, err = timeutil.ParseTime(probeShowOpts.since, now); err != nil {
+			return f, fmt.Errorf("parsing --since: %w", err)
+		}
+	}
+	if probeShowOpts.last != "" {
+		last, err := timeutil.ParseDuration(probeShowOpts.last)
+		if err != nil {
+			return f, fmt.Errorf("parsing --last: %w", err)
+		}
+		end := f.To
+		if end.IsZero() {
+			end = now
+		}
+		f.From = end.Add(-last)
+	}
+	if probeShowOpts.step != "" {
+		if f.Step, err = timeutil.ParseDuration(probeShowOpts.step); err != nil {
+			return f, fmt.Errorf("parsing --step: %w", err)
+		}
+	}
 
-	// Here you will define your flags and configuration settings.
+	return f, nil
+}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// probeShowCmd.PersistentFlags().String("foo", "", "A help for foo")
+func init() {
+	probeCmd.AddCommand(probeShowCmd)
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// probeShowCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	probeShowCmd.Flags().StringVar(&probeShowOpts.since, "since", "", "Show measurements taken after this time")
+	probeShowCmd.Flags().StringVar(&probeShowOpts.until, "until", "", "Show measurements taken before this time")
+	probeShowCmd.Flags().StringVar(&probeShowOpts.last, "last", "", "Show measurements of the last period (e.g. 30m, 1h, 7d)")
+	probeShowCmd.Flags().StringVar(&probeShowOpts.step, "step", "", "Downsample measurements averaging them in windows of this size (e.g. 5m)")
+	probeShowCmd.Flags().StringSliceVar(&probeShowOpts.metrics, "metric", nil, "Only show these metrics (e.g. cpu_usage, temperature), may be repeated")
+	probeShowCmd.Flags().BoolVar(&probeShowOpts.processes, "processes", false, "Show the processes using the most CPU and memory instead of the metrics")
+	probeShowCmd.Flags().StringVar(&probeShowOpts.at, "at", "", "Time of the processes snapshot to show, the closest one is used (default now)")
 }
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/adapter"
+	"github.com/eldius/rpi-system-monitor/internal/output"
+	"github.com/eldius/rpi-system-monitor/internal/timeutil"
+	"github.com/spf13/cobra"
+)
+
+var queryOpts struct {
+	start  string
+	end    string
+	step   string
+	output string
+}
+
+// queryCmd represents the query command
+var queryCmd = &cobra.Command{
+	Use:   "query <promql>",
+	Short: "Run a PromQL query against the local database",
+	Long: `Run a PromQL query against the local database.
+
+Metric names are the ones written by the probes (temperature, cpu_usage,
+memory_usage_percentage, ...). Without --step an instant query is
+evaluated at --end, otherwise a range query is evaluated from --start
+to --end.
+
+Times accept RFC 3339 dates, "2006-01-02 15:04:05" (local time), unix
+timestamps or values relative to now such as "now-1h" or "-30m".`,
+	Example: `  agent query 'avg_over_time(temperature[1h])'
+  agent query 'max_over_time(cpu_usage[5m])' --start now-6h --step 5m`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		_, err := output.ParseFormat(queryOpts.output)
+		return err
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := output.ParseFormat(queryOpts.output)
+		now := time.Now()
+		end, err := timeutil.ParseTime(queryOpts.end, now)
+		if err != nil {
+			return fmt.Errorf("parsing --end: %w", err)
+		}
+		start, err := timeutil.ParseTime(queryOpts.start, now)
+		if err != nil {
+			return fmt.Errorf("parsing --start: %w", err)
+		}
+		var step time.Duration
+		if queryOpts.step != "" {
+			if step, err = timeutil.ParseDuration(queryOpts.step); err != nil {
+				return fmt.Errorf("parsing --step: %w", err)
+			}
+		}
+
+		result, err := adapter.Query(cmd.Context(), args[0], start, end, step)
+		if err != nil {
+			return err
+		}
+
+		if format == output.FormatTable {
+			// the other formats include the warnings
+			for _, w := range result.Warnings {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", w)
+			}
+		}
+		return output.WriteQuery(cmd.OutOrStdout(), format, result)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+
+	queryCmd.Flags().StringVar(&queryOpts.start, "start", "now-1h", "Start of the range query")
+	queryCmd.Flags().StringVar(&queryOpts.end, "end", "now", "End of the range query, or evaluation time of an instant query")
+	queryCmd.Flags().StringVar(&queryOpts.step, "step", "", "Resolution of the range query (instant query when empty)")
+	queryCmd.Flags().StringVarP(&queryOpts.output, "output", "o", string(output.FormatTable), fmt.Sprintf("Output format, one of %v", []output.Format{output.FormatTable, output.FormatJSON, output.FormatYAML}))
+}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"errors"
+	"os"
+
 	"github.com/eldius/initial-config-go/configs"
 	"github.com/eldius/initial-config-go/setup"
 	"github.com/eldius/rpi-system-monitor/internal/config"
-	"os"
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
 
 	"github.com/spf13/cobra"
 )
@@ -22,6 +25,28 @@ var rootCmd = &cobra.Command{
 		setup.WithConfigFileToBeUsed(cfgFile),
 		setup.WithProps(
 			config.TemperatureProbeEnabledProp,
+			config.ThrottlingProbeEnabledProp,
+			config.ThrottlingProbeCommandProp,
+			config.FilesystemProbeEnabledProp,
+			config.FilesystemIncludeFSTypesProp,
+			config.FilesystemExcludeFSTypesProp,
+			config.FilesystemIncludeMountpointsProp,
+			config.FilesystemExcludeMountpointsProp,
+			config.WirelessProbeEnabledProp,
+			config.WirelessProbeIwCommandProp,
+			config.ProcessesProbeEnabledProp,
+			config.ProcessesProbeTopProp,
+			config.W1ProbeEnabledProp,
+			config.W1ProbeDevicesPathProp,
+			config.W1ProbeNamesProp,
+			config.SamplingIntervalProp,
+			config.SamplingJitterProp,
+			config.MetricsEnabledProp,
+			config.MetricsListenAddrProp,
+			config.APIListenAddrProp,
+			config.StoragePathProp,
+			config.StorageRetentionProp,
+			config.StorageMaxSizeProp,
 		),
 		setup.WithDefaultValues(map[string]any{
 			configs.LogFormatKey:     configs.LogFormatJSON,
@@ -29,7 +54,12 @@ var rootCmd = &cobra.Command{
 			configs.LogOutputFileKey: "execution.log",
 		}),
 	),
-	PersistentPostRunE: setup.PersistentPostRunE,
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		return errors.Join(
+			persistence.Close(),
+			setup.PersistentPostRunE(cmd, args),
+		)
+	},
 }
 
 var cfgFile string
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/adapter"
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/exporter"
+	"github.com/eldius/rpi-system-monitor/internal/scheduler"
+	"github.com/eldius/rpi-system-monitor/internal/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the agent as a daemon, sampling probes periodically",
+	Long: `Run the agent as a daemon, sampling probes periodically.
+
+Measurements are taken every 'monitor.server.interval' (plus a random
+delay up to 'monitor.server.jitter') and persisted to the local database
+until the process receives SIGINT or SIGTERM. When it's stopped by a
+system shutdown, as reported by systemd, a shutdown event is recorded,
+so the following reboot isn't reported as unexpected by 'events'.
+
+When 'monitor.server.metrics.enabled' is set the latest measurement is
+also exposed in the Prometheus format at
+http://<monitor.server.metrics.listen>/metrics.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		g, ctx := errgroup.WithContext(ctx)
+		startSampling(ctx, g)
+
+		if config.GetMetricsEnabled() {
+			mux := http.NewServeMux()
+			mux.Handle("GET /metrics", exporter.NewHandler(adapter.Latest))
+			g.Go(func() error {
+				return server.Run(ctx, config.GetMetricsListenAddr(), mux)
+			})
+		}
+
+		return g.Wait()
+	},
+}
+
+// startSampling runs the measurement scheduler in g until ctx is done,
+// recording a shutdown event if the system is shutting down.
+func startSampling(ctx context.Context, g *errgroup.Group) {
+	s := scheduler.New(config.GetSamplingInterval(), config.GetSamplingJitter(), func(ctx context.Context) error {
+		_, err := adapter.Measure(ctx)
+		return err
+	})
+	g.Go(func() error {
+		if err := s.Run(ctx); err != nil {
+			return err
+		}
+		return adapter.RecordShutdown(context.WithoutCancel(ctx))
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+
+	runCmd.Flags().Duration("interval", config.SamplingIntervalProp.Value.(time.Duration), "Interval between measurements")
+	runCmd.Flags().Duration("jitter", config.SamplingJitterProp.Value.(time.Duration), "Maximum random delay added to each measurement")
+	runCmd.Flags().Bool("metrics", false, "Expose the Prometheus metrics endpoint")
+	runCmd.Flags().String("metrics-listen", config.MetricsListenAddrProp.Value.(string), "Address the Prometheus metrics endpoint listens on")
+
+	_ = viper.BindPFlag(config.SamplingIntervalProp.Key, runCmd.Flags().Lookup("interval"))
+	_ = viper.BindPFlag(config.SamplingJitterProp.Key, runCmd.Flags().Lookup("jitter"))
+	_ = viper.BindPFlag(config.MetricsEnabledProp.Key, runCmd.Flags().Lookup("metrics"))
+	_ = viper.BindPFlag(config.MetricsListenAddrProp.Key, runCmd.Flags().Lookup("metrics-listen"))
+}
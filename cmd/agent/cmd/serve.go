@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/eldius/rpi-system-monitor/internal/adapter"
+	"github.com/eldius/rpi-system-monitor/internal/api"
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/exporter"
+	"github.com/eldius/rpi-system-monitor/internal/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Sample probes periodically and serve them over HTTP",
+	Long: `Sample probes periodically and serve them over HTTP.
+
+Works like 'run', also exposing the following endpoints on
+'monitor.server.api.listen':
+
+  GET /api/v1/current   latest measurement
+  GET /api/v1/series    stored samples of a metric, accepts the
+                        'metric', 'from', 'to' (RFC 3339 or unix
+                        seconds, defaults to the last hour) and
+                        'step' (averaging window) parameters
+  GET /api/v1/query     evaluates the PromQL expression in 'query',
+                        as an instant query at 'end' or, when 'step'
+                        is set, as a range query from 'start' to 'end'
+  GET /metrics          latest measurement in the Prometheus format`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		g, ctx := errgroup.WithContext(ctx)
+		startSampling(ctx, g)
+
+		mux := http.NewServeMux()
+		mux.Handle("/api/v1/", api.NewHandler(adapter.Latest, adapter.Series, adapter.Query))
+		mux.Handle("GET /metrics", exporter.NewHandler(adapter.Latest))
+		g.Go(func() error {
+			return server.Run(ctx, config.GetAPIListenAddr(), mux)
+		})
+
+		return g.Wait()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("listen", config.APIListenAddrProp.Value.(string), "Address the HTTP API listens on")
+
+	_ = viper.BindPFlag(config.APIListenAddrProp.Key, serveCmd.Flags().Lookup("listen"))
+}
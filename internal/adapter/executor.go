@@ -2,19 +2,211 @@ package adapter
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/eldius/rpi-system-monitor/internal/model"
 	"github.com/eldius/rpi-system-monitor/internal/persistence"
 	"github.com/eldius/rpi-system-monitor/internal/telemetry"
+	"github.com/prometheus/prometheus/storage"
 )
 
 type MeasureFunc func(ctx context.Context) (model.ProbesResult, error)
 
+type LatestFunc func() (model.ProbesResult, bool)
+
+type SeriesFunc func(ctx context.Context, metric string, from, to time.Time, step time.Duration) ([]persistence.Series, error)
+
+type QueryFunc func(ctx context.Context, qs string, start, end time.Time, step time.Duration) (*persistence.QueryResult, error)
+
+// lastBoot caches the boot state file, read on the first measurement.
+var lastBoot struct {
+	sync.Mutex
+	state persistence.BootState
+	ok    bool
+	read  bool
+}
+
+// counters keeps the probe counters between measurements, so their
+// rates cover the whole interval.
+var counters struct {
+	sync.Mutex
+	c telemetry.Counters
+}
+
+var latest struct {
+	sync.RWMutex
+	result model.ProbesResult
+	ok     bool
+}
+
 func Measure(ctx context.Context) (model.ProbesResult, error) {
-	probesResult := telemetry.Measure(ctx)
-	return probesResult, persistence.Persist(ctx, &probesResult)
+	counters.Lock()
+	probesResult := telemetry.Measure(ctx, &counters.c)
+	counters.Unlock()
+
+	latest.Lock()
+	latest.result = probesResult
+	latest.ok = true
+	latest.Unlock()
+
+	store, err := persistence.Default()
+	if err != nil {
+		return probesResult, err
+	}
+	if err := detectReboot(ctx, store, &probesResult); err != nil {
+		return probesResult, err
+	}
+	if err := store.Persist(ctx, &probesResult); err != nil {
+		return probesResult, err
+	}
+	return probesResult, persistProcesses(ctx, &probesResult)
+}
+
+// detectReboot records a reboot event, at the boot time, when the
+// system rebooted since the boot kept in the boot state. The reboot is
+// unexpected when no shutdown event was recorded after the state was
+// written.
+//
+// The state is only rewritten when the boot changes, unless the boot ID
+// is unknown and the uptime of every measurement is needed to tell.
+func detectReboot(ctx context.Context, store *persistence.Store, result *model.ProbesResult) error {
+	if result.Uptime.BootTime.IsZero() {
+		return nil
+	}
+
+	lastBoot.Lock()
+	defer lastBoot.Unlock()
+
+	if !lastBoot.read {
+		state, ok, err := persistence.ReadBootState(persistence.BootStatePath())
+		if err != nil {
+			return err
+		}
+		lastBoot.state, lastBoot.ok, lastBoot.read = state, ok, true
+	}
+	prev, ok := lastBoot.state, lastBoot.ok
+	rebooted := ok && result.Uptime.RebootedSince(prev.Uptime)
+
+	if !ok || rebooted || result.Uptime.BootID == "" {
+		state := persistence.BootState{Uptime: result.Uptime, Timestamp: result.Timestamp}
+		if err := persistence.WriteBootState(persistence.BootStatePath(), state); err != nil {
+			return err
+		}
+		lastBoot.state, lastBoot.ok = state, true
+	}
+	if !rebooted {
+		return nil
+	}
+
+	events, err := store.Events(ctx, prev.Timestamp, result.Timestamp)
+	if err != nil {
+		return err
+	}
+	unexpected := !slices.ContainsFunc(events, func(e model.EventResult) bool {
+		return e.Type == model.EventShutdown
+	})
+	slog.With("boot_time", result.Uptime.BootTime, "unexpected", unexpected).InfoContext(ctx, "system rebooted since the last measurement")
+
+	event := model.EventResult{
+		Type:       model.EventReboot,
+		Timestamp:  result.Uptime.BootTime,
+		Unexpected: unexpected,
+	}
+	err = store.PersistEvent(ctx, event)
+	if errors.Is(err, storage.ErrOutOfBounds) {
+		// the boot time is older than the database accepts when the
+		// clock was wrong at boot, e.g. on boards without a RTC, the
+		// reboot is then marked at the measurement detecting it
+		event.Timestamp = result.Timestamp
+		err = store.PersistEvent(ctx, event)
+	}
+	return err
+}
+
+// RecordShutdown records a shutdown event when the system is shutting
+// down, so the following reboot isn't reported as unexpected. It's
+// meant to be called when the agent is stopped.
+func RecordShutdown(ctx context.Context) error {
+	if !telemetry.SystemStopping(ctx) {
+		return nil
+	}
+	store, err := persistence.Default()
+	if err != nil {
+		return err
+	}
+	return store.PersistEvent(ctx, model.EventResult{
+		Type:      model.EventShutdown,
+		Timestamp: time.Now(),
+	})
+}
+
+// Events returns the events that happened between from and to.
+func Events(ctx context.Context, from, to time.Time) ([]model.EventResult, error) {
+	store, err := persistence.Default()
+	if err != nil {
+		return nil, err
+	}
+	return store.Events(ctx, from, to)
+}
+
+// persistProcesses writes the processes snapshot of result, if the
+// processes probe is enabled, to the processes store.
+func persistProcesses(ctx context.Context, result *model.ProbesResult) error {
+	if result.Processes == nil {
+		return nil
+	}
+	store, err := persistence.DefaultProcesses()
+	if err != nil {
+		return err
+	}
+	return store.Persist(ctx, &model.ProcessesSnapshot{
+		Timestamp:       result.Timestamp,
+		ProcessesResult: *result.Processes,
+	})
+}
+
+// ProcessesAt returns the processes snapshot taken closest to at.
+func ProcessesAt(ctx context.Context, at time.Time) (*model.ProcessesSnapshot, error) {
+	store, err := persistence.DefaultProcesses()
+	if err != nil {
+		return nil, err
+	}
+	return store.At(ctx, at)
+}
+
+// Latest returns the last measurement taken by Measure in this process.
+// The boolean is false while no measurement was taken yet.
+func Latest() (model.ProbesResult, bool) {
+	latest.RLock()
+	defer latest.RUnlock()
+
+	return latest.result, latest.ok
+}
+
+func Get(ctx context.Context, f persistence.Filter) ([]model.ProbesResult, error) {
+	store, err := persistence.Default()
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(ctx, f)
+}
+
+func Series(ctx context.Context, metric string, from, to time.Time, step time.Duration) ([]persistence.Series, error) {
+	store, err := persistence.Default()
+	if err != nil {
+		return nil, err
+	}
+	return store.Series(ctx, metric, from, to, step)
 }
 
-func Get(ctx context.Context) ([]model.ProbesResult, error) {
-	return persistence.Get(ctx)
+func Query(ctx context.Context, qs string, start, end time.Time, step time.Duration) (*persistence.QueryResult, error) {
+	store, err := persistence.Default()
+	if err != nil {
+		return nil, err
+	}
+	return store.Query(ctx, qs, start, end, step)
 }
@@ -0,0 +1,110 @@
+package adapter
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
+	"github.com/spf13/viper"
+)
+
+// restart forgets the cached boot state, as a new agent process would.
+func restart() {
+	lastBoot.Lock()
+	lastBoot.state, lastBoot.ok, lastBoot.read = persistence.BootState{}, false, false
+	lastBoot.Unlock()
+}
+
+func TestDetectReboot(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	viper.Set(config.StoragePathProp.Key, dir)
+	t.Cleanup(func() { viper.Set(config.StoragePathProp.Key, nil) })
+	t.Cleanup(restart)
+
+	store, err := persistence.Open(filepath.Join(dir, "tsdb"), persistence.WithRetention(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	measurement := func(bootID string, bootTime time.Time, at time.Time) *model.ProbesResult {
+		return &model.ProbesResult{
+			Timestamp: at,
+			Uptime: model.UptimeResult{
+				UptimeSeconds: int64(at.Sub(bootTime).Seconds()),
+				BootTime:      bootTime,
+				BootID:        bootID,
+			},
+		}
+	}
+	events := func() []model.EventResult {
+		t.Helper()
+		events, err := store.Events(ctx, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return events
+	}
+
+	// first run, the boot is only written to the state file
+	restart()
+	first := measurement("boot-1", start, start.Add(10*time.Minute))
+	if err := detectReboot(ctx, store, first); err != nil {
+		t.Fatal(err)
+	}
+	state, ok, err := persistence.ReadBootState(persistence.BootStatePath())
+	if err != nil || !ok || state.Uptime.BootID != "boot-1" || !state.Timestamp.Equal(first.Timestamp) {
+		t.Fatalf("boot state after the first run = %+v, %v, %v", state, ok, err)
+	}
+	if e := events(); len(e) != 0 {
+		t.Fatalf("events after the first run = %+v, want none", e)
+	}
+
+	// same boot, in a new process, the state isn't rewritten
+	restart()
+	if err := detectReboot(ctx, store, measurement("boot-1", start, start.Add(20*time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	state, _, _ = persistence.ReadBootState(persistence.BootStatePath())
+	if !state.Timestamp.Equal(first.Timestamp) {
+		t.Errorf("boot state rewritten on the same boot at %v", state.Timestamp)
+	}
+	if e := events(); len(e) != 0 {
+		t.Fatalf("events on the same boot = %+v, want none", e)
+	}
+
+	// new boot without a shutdown recorded before it
+	restart()
+	secondBoot := start.Add(30 * time.Minute)
+	if err := detectReboot(ctx, store, measurement("boot-2", secondBoot, secondBoot.Add(time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	want := []model.EventResult{{Type: model.EventReboot, Timestamp: secondBoot, Unexpected: true}}
+	if e := events(); len(e) != 1 || e[0].Type != want[0].Type || !e[0].Timestamp.Equal(want[0].Timestamp) || e[0].Unexpected != want[0].Unexpected {
+		t.Fatalf("events after a new boot = %+v, want %+v", e, want)
+	}
+
+	// new boot after a recorded shutdown
+	if err := store.PersistEvent(ctx, model.EventResult{Type: model.EventShutdown, Timestamp: secondBoot.Add(5 * time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	restart()
+	thirdBoot := secondBoot.Add(6 * time.Minute)
+	if err := detectReboot(ctx, store, measurement("boot-3", thirdBoot, thirdBoot.Add(time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	e := events()
+	if len(e) != 3 || e[2].Type != model.EventReboot || !e[2].Timestamp.Equal(thirdBoot) || e[2].Unexpected {
+		t.Fatalf("events after a shutdown and a new boot = %+v, want an expected reboot at %v", e, thirdBoot)
+	}
+	state, _, _ = persistence.ReadBootState(persistence.BootStatePath())
+	if state.Uptime.BootID != "boot-3" {
+		t.Errorf("boot state after a new boot = %+v, want boot-3", state)
+	}
+}
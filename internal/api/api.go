@@ -0,0 +1,189 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/adapter"
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
+	"github.com/eldius/rpi-system-monitor/internal/timeutil"
+)
+
+const (
+	defaultRange = time.Hour
+
+	// maxPoints limits the number of buckets a single series query may
+	// return, the same limit used by the Prometheus HTTP API.
+	maxPoints = 11000
+)
+
+var (
+	ErrTooManyPoints = fmt.Errorf("exceeded maximum resolution of %d points per series, try a bigger step", maxPoints)
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type seriesResponse struct {
+	Metric string               `json:"metric"`
+	From   time.Time            `json:"from"`
+	To     time.Time            `json:"to"`
+	Step   string               `json:"step,omitempty"`
+	Series []persistence.Series `json:"series"`
+}
+
+type handler struct {
+	latest adapter.LatestFunc
+	series adapter.SeriesFunc
+	query  adapter.QueryFunc
+}
+
+// NewHandler returns the http.Handler of the /api/v1 endpoints.
+func NewHandler(latest adapter.LatestFunc, series adapter.SeriesFunc, query adapter.QueryFunc) http.Handler {
+	h := &handler{
+		latest: latest,
+		series: series,
+		query:  query,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/current", h.current)
+	mux.HandleFunc("GET /api/v1/series", h.querySeries)
+	mux.HandleFunc("GET /api/v1/query", h.promQL)
+	return mux
+}
+
+func (h *handler) current(w http.ResponseWriter, r *http.Request) {
+	result, ok := h.latest()
+	if !ok {
+		writeError(w, r, http.StatusServiceUnavailable, errors.New("no measurement taken yet"))
+		return
+	}
+	writeJSON(w, r, http.StatusOK, result)
+}
+
+func (h *handler) querySeries(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	now := time.Now()
+	to, err := parseTime(q.Get("to"), now, now)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid 'to' parameter: %w", err))
+		return
+	}
+	from, err := parseTime(q.Get("from"), now, to.Add(-defaultRange))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid 'from' parameter: %w", err))
+		return
+	}
+	step, err := parseStep(q.Get("step"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid 'step' parameter: %w", err))
+		return
+	}
+	if step > 0 && to.Sub(from)/step > maxPoints {
+		writeError(w, r, http.StatusBadRequest, ErrTooManyPoints)
+		return
+	}
+
+	metric := q.Get("metric")
+	series, err := h.series(r.Context(), metric, from, to, step)
+	if errors.Is(err, persistence.ErrMetricRequired) || errors.Is(err, persistence.ErrInvalidRange) {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := seriesResponse{
+		Metric: metric,
+		From:   from,
+		To:     to,
+		Series: series,
+	}
+	if step > 0 {
+		resp.Step = step.String()
+	}
+	writeJSON(w, r, http.StatusOK, resp)
+}
+
+func (h *handler) promQL(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	qs := q.Get("query")
+	if qs == "" {
+		writeError(w, r, http.StatusBadRequest, errors.New("'query' parameter is required"))
+		return
+	}
+
+	now := time.Now()
+	end, err := parseTime(q.Get("end"), now, now)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid 'end' parameter: %w", err))
+		return
+	}
+	start, err := parseTime(q.Get("start"), now, end)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid 'start' parameter: %w", err))
+		return
+	}
+	step, err := parseStep(q.Get("step"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid 'step' parameter: %w", err))
+		return
+	}
+	if step > 0 && end.Sub(start)/step > maxPoints {
+		writeError(w, r, http.StatusBadRequest, ErrTooManyPoints)
+		return
+	}
+
+	result, err := h.query(r.Context(), qs, start, end, step)
+	if errors.Is(err, persistence.ErrInvalidRange) {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		// parse and evaluation errors are caused by the query itself
+		writeError(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, result)
+}
+
+// parseTime parses v with timeutil.ParseTime, returning def when v
+// is empty.
+func parseTime(v string, now, def time.Time) (time.Time, error) {
+	if v == "" {
+		return def, nil
+	}
+	return timeutil.ParseTime(v, now)
+}
+
+// parseStep parses v with timeutil.ParseDuration, returning zero when
+// v is empty.
+func parseStep(v string) (time.Duration, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return timeutil.ParseDuration(v)
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.With("error", err, "path", r.URL.Path).ErrorContext(r.Context(), "failed to write response")
+	}
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	slog.With("error", err, "path", r.URL.Path, "status", status).DebugContext(r.Context(), "request failed")
+	writeJSON(w, r, status, errorResponse{Error: err.Error()})
+}
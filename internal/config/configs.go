@@ -1,7 +1,14 @@
 package config
 
 import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/eldius/initial-config-go/setup"
+	"github.com/eldius/rpi-system-monitor/internal/timeutil"
 	"github.com/spf13/viper"
 )
 
@@ -21,6 +28,116 @@ var (
 		Value: true,
 	}
 
+	ThrottlingProbeEnabledProp = setup.Prop{
+		Key:   "monitor.server.throttling_probe.enabled",
+		Value: true,
+	}
+
+	ThrottlingProbeCommandProp = setup.Prop{
+		Key:   "monitor.server.throttling_probe.command",
+		Value: "vcgencmd",
+	}
+
+	FilesystemProbeEnabledProp = setup.Prop{
+		Key:   "monitor.server.filesystem_probe.enabled",
+		Value: true,
+	}
+
+	FilesystemIncludeFSTypesProp = setup.Prop{
+		Key:   "monitor.server.filesystem_probe.include_fstypes",
+		Value: []string{},
+	}
+
+	FilesystemExcludeFSTypesProp = setup.Prop{
+		Key:   "monitor.server.filesystem_probe.exclude_fstypes",
+		Value: []string{"squashfs"},
+	}
+
+	FilesystemIncludeMountpointsProp = setup.Prop{
+		Key:   "monitor.server.filesystem_probe.include_mountpoints",
+		Value: []string{},
+	}
+
+	FilesystemExcludeMountpointsProp = setup.Prop{
+		Key:   "monitor.server.filesystem_probe.exclude_mountpoints",
+		Value: []string{},
+	}
+
+	WirelessProbeEnabledProp = setup.Prop{
+		Key:   "monitor.server.wireless_probe.enabled",
+		Value: true,
+	}
+
+	WirelessProbeIwCommandProp = setup.Prop{
+		Key:   "monitor.server.wireless_probe.iw_command",
+		Value: "iw",
+	}
+
+	ProcessesProbeEnabledProp = setup.Prop{
+		Key:   "monitor.server.processes_probe.enabled",
+		Value: true,
+	}
+
+	ProcessesProbeTopProp = setup.Prop{
+		Key:   "monitor.server.processes_probe.top",
+		Value: 5,
+	}
+
+	W1ProbeEnabledProp = setup.Prop{
+		Key:   "monitor.server.w1_probe.enabled",
+		Value: true,
+	}
+
+	W1ProbeDevicesPathProp = setup.Prop{
+		Key:   "monitor.server.w1_probe.devices_path",
+		Value: "/sys/bus/w1/devices",
+	}
+
+	W1ProbeNamesProp = setup.Prop{
+		Key:   "monitor.server.w1_probe.names",
+		Value: map[string]string{},
+	}
+
+	SamplingIntervalProp = setup.Prop{
+		Key:   "monitor.server.interval",
+		Value: 10 * time.Second,
+	}
+
+	SamplingJitterProp = setup.Prop{
+		Key:   "monitor.server.jitter",
+		Value: 500 * time.Millisecond,
+	}
+
+	MetricsEnabledProp = setup.Prop{
+		Key:   "monitor.server.metrics.enabled",
+		Value: false,
+	}
+
+	MetricsListenAddrProp = setup.Prop{
+		Key:   "monitor.server.metrics.listen",
+		Value: ":9110",
+	}
+
+	APIListenAddrProp = setup.Prop{
+		Key:   "monitor.server.api.listen",
+		Value: ":8080",
+	}
+
+	StoragePathProp = setup.Prop{
+		Key:   "monitor.storage.path",
+		Value: defaultStoragePath(),
+	}
+
+	StorageRetentionProp = setup.Prop{
+		Key:   "monitor.storage.retention",
+		Value: "15d",
+	}
+
+	StorageMaxSizeProp = setup.Prop{
+		Key:   "monitor.storage.max_size",
+		Value: 0,
+	}
+
 	CfgFileLocations = []string{
 		"~/.config/rpi-monitor",
 		"~/.rpi-monitor",
@@ -34,6 +151,137 @@ func GetTemperatureProbeEnabled() bool {
 	return viper.GetBool(TemperatureProbeEnabledProp.Key)
 }
 
+// GetThrottlingProbeCommand returns the vcgencmd executable used to
+// read the Raspberry Pi throttling state and voltages.
+func GetThrottlingProbeCommand() string {
+	return viper.GetString(ThrottlingProbeCommandProp.Key)
+}
+
+// GetFilesystemIncludeFSTypes returns the filesystem types measured by
+// the filesystem probe, empty meaning all of them.
+func GetFilesystemIncludeFSTypes() []string {
+	return viper.GetStringSlice(FilesystemIncludeFSTypesProp.Key)
+}
+
+// GetFilesystemExcludeFSTypes returns the filesystem types skipped by
+// the filesystem probe.
+func GetFilesystemExcludeFSTypes() []string {
+	return viper.GetStringSlice(FilesystemExcludeFSTypesProp.Key)
+}
+
+// GetFilesystemIncludeMountpoints returns the mountpoint patterns
+// (e.g. "/mnt/*") measured by the filesystem probe, empty meaning all
+// of them.
+func GetFilesystemIncludeMountpoints() []string {
+	return viper.GetStringSlice(FilesystemIncludeMountpointsProp.Key)
+}
+
+// GetFilesystemExcludeMountpoints returns the mountpoint patterns
+// skipped by the filesystem probe.
+func GetFilesystemExcludeMountpoints() []string {
+	return viper.GetStringSlice(FilesystemExcludeMountpointsProp.Key)
+}
+
+// GetWirelessProbeIwCommand returns the iw executable used to read the
+// SSID and bitrate of wireless links, empty meaning it isn't used.
+func GetWirelessProbeIwCommand() string {
+	return viper.GetString(WirelessProbeIwCommandProp.Key)
+}
+
+// GetProcessesProbeTop returns how many processes are kept in each
+// snapshot ranking, by CPU and by resident memory.
+func GetProcessesProbeTop() int {
+	return viper.GetInt(ProcessesProbeTopProp.Key)
+}
+
+// GetW1ProbeDevicesPath returns the directory listing the 1-Wire
+// devices, where the DS18B20 sensors are looked for.
+func GetW1ProbeDevicesPath() string {
+	return viper.GetString(W1ProbeDevicesPathProp.Key)
+}
+
+// GetW1ProbeNames returns the friendly names of the DS18B20 sensors by
+// their 1-Wire ID (e.g. "28-0316a2795bff").
+func GetW1ProbeNames() map[string]string {
+	return viper.GetStringMapString(W1ProbeNamesProp.Key)
+}
+
+// GetSamplingInterval returns the interval between two measurements
+// taken by the agent daemon.
+func GetSamplingInterval() time.Duration {
+	return viper.GetDuration(SamplingIntervalProp.Key)
+}
+
+// GetSamplingJitter returns the maximum random delay added to each
+// scheduled measurement.
+func GetSamplingJitter() time.Duration {
+	return viper.GetDuration(SamplingJitterProp.Key)
+}
+
+// GetMetricsEnabled returns whether the daemon exposes the Prometheus
+// metrics endpoint.
+func GetMetricsEnabled() bool {
+	return viper.GetBool(MetricsEnabledProp.Key)
+}
+
+// GetMetricsListenAddr returns the address the Prometheus metrics
+// endpoint listens on.
+func GetMetricsListenAddr() string {
+	return viper.GetString(MetricsListenAddrProp.Key)
+}
+
+// GetAPIListenAddr returns the address the HTTP API listens on.
+func GetAPIListenAddr() string {
+	return viper.GetString(APIListenAddrProp.Key)
+}
+
+// GetStoragePath returns the directory holding the agent's data.
+func GetStoragePath() string {
+	p := viper.GetString(StoragePathProp.Key)
+	if home, err := os.UserHomeDir(); err == nil {
+		if p == "~" {
+			p = home
+		} else if rest, ok := strings.CutPrefix(p, "~/"); ok {
+			p = filepath.Join(home, rest)
+		}
+	}
+	if abs, err := filepath.Abs(p); err == nil {
+		p = abs
+	}
+	return p
+}
+
+// defaultStoragePath returns /var/lib/rpi-monitor when running as root
+// (usually as a system service) and the user's XDG data directory
+// otherwise.
+func defaultStoragePath() string {
+	if os.Geteuid() == 0 {
+		return "/var/lib/rpi-monitor"
+	}
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "rpi-monitor")
+	}
+	return "~/.local/share/rpi-monitor"
+}
+
+// GetStorageRetention returns for how long samples are kept in the
+// database, zero meaning forever. Accepts Go durations and days ("30d").
+func GetStorageRetention() time.Duration {
+	v := viper.GetString(StorageRetentionProp.Key)
+	d, err := timeutil.ParseDuration(v)
+	if err != nil {
+		d, _ = timeutil.ParseDuration(StorageRetentionProp.Value.(string))
+		slog.With("error", err, "default", d.String()).Warn("invalid storage retention, using default value")
+	}
+	return d
+}
+
+// GetStorageMaxSize returns the maximum number of bytes the database
+// blocks may use, zero meaning unlimited. Accepts sizes such as "512MB".
+func GetStorageMaxSize() int64 {
+	return int64(viper.GetSizeInBytes(StorageMaxSizeProp.Key))
+}
+
 func GetVersionInfo() map[string]string {
 	return map[string]string{
 		"version":   Version,
@@ -0,0 +1,64 @@
+package exporter
+
+import (
+	"net/http"
+
+	"github.com/eldius/rpi-system-monitor/internal/adapter"
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+const (
+	lastMeasurementMetric = "last_measurement_timestamp_seconds"
+)
+
+var (
+	_ prometheus.Collector = &collector{}
+)
+
+// collector exposes the latest measurement as gauges using the same
+// names and labels written to the database. It never measures by
+// itself, so scrapes don't wait for the probes.
+type collector struct {
+	latest adapter.LatestFunc
+}
+
+// Describe sends no descriptors, making it an unchecked collector,
+// since the label set depends on the probes enabled.
+func (c *collector) Describe(_ chan<- *prometheus.Desc) {}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	result, ok := c.latest()
+	if !ok {
+		return
+	}
+
+	for _, s := range persistence.Samples(&result) {
+		var names, values []string
+		s.Labels.Range(func(l labels.Label) {
+			if l.Name == persistence.DimensionLabelName {
+				return
+			}
+			names = append(names, l.Name)
+			values = append(values, l.Value)
+		})
+		desc := prometheus.NewDesc(s.Name(), "Latest "+s.Name()+" measurement.", names, nil)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, s.Value, values...)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(lastMeasurementMetric, "Unix time of the latest measurement.", nil, nil),
+		prometheus.GaugeValue,
+		float64(result.Timestamp.UnixMilli())/1000,
+	)
+}
+
+// NewHandler returns an http.Handler serving the measurements returned
+// by latest in the Prometheus exposition format.
+func NewHandler(latest adapter.LatestFunc) http.Handler {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(&collector{latest: latest})
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
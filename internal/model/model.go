@@ -1,19 +1,91 @@
 package model
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"time"
 )
 
 type CPUResult struct {
-	CPUUsage float64 `json:"cpu_usage"`
-	CPUCount int64   `json:"cpu_count"`
+	CPUUsage float64         `json:"cpu_usage"`
+	CPUCount int64           `json:"cpu_count"`
+	Cores    []CPUCoreResult `json:"cores,omitempty"`
+	Times    []CPUTimeResult `json:"times,omitempty"`
+
+	Frequencies []CPUFrequencyResult `json:"frequencies,omitempty"`
+}
+
+// CPUCoreResult is the usage of a single logical CPU.
+type CPUCoreResult struct {
+	Core  int     `json:"core"`
+	Usage float64 `json:"usage"`
+}
+
+// CPUTimeModes are the modes reported by the CPU time breakdown, in
+// the order they're displayed.
+var CPUTimeModes = []string{"user", "nice", "system", "idle", "iowait", "irq", "softirq", "steal"}
+
+// CPUTimeResult is the share of the CPU time spent in a mode during
+// the sampling window.
+type CPUTimeResult struct {
+	Mode       string  `json:"mode"`
+	Percentage float64 `json:"percentage"`
+}
+
+// CPUFrequencyResult is the clock of a single logical CPU, in Hz, and
+// the cpufreq governor scaling it.
+type CPUFrequencyResult struct {
+	Core     int    `json:"core"`
+	Current  int64  `json:"current"`
+	Min      int64  `json:"min"`
+	Max      int64  `json:"max"`
+	Governor string `json:"governor,omitempty"`
 }
 
 type MemoryResult struct {
 	MemoryUsagePercentage float64 `json:"memory_usage_percentage"`
 	UsedMemory            int64   `json:"used_memory"`
 	TotalMemory           int64   `json:"total_memory"`
+
+	// AvailableMemory is the memory that can be allocated without
+	// swapping, which includes most of the page cache.
+	AvailableMemory int64 `json:"available_memory"`
+	BuffersMemory   int64 `json:"buffers_memory"`
+	CachedMemory    int64 `json:"cached_memory"`
+	SharedMemory    int64 `json:"shared_memory"`
+	DirtyMemory     int64 `json:"dirty_memory"`
+	WritebackMemory int64 `json:"writeback_memory"`
+
+	UsedSwap              int64   `json:"used_swap"`
+	TotalSwap             int64   `json:"total_swap"`
+	SwapInBytesPerSecond  float64 `json:"swap_in_bytes_per_second"`
+	SwapOutBytesPerSecond float64 `json:"swap_out_bytes_per_second"`
+
+	Zram []ZramResult `json:"zram,omitempty"`
+}
+
+// ZramResult is the usage of a zram compressed block device, usually
+// used as swap.
+type ZramResult struct {
+	Device string `json:"device"`
+	// DiskSize is the uncompressed capacity of the device.
+	DiskSize int64 `json:"disk_size"`
+	// OriginalDataSize is the uncompressed size of the stored data,
+	// CompressedDataSize its compressed size and MemoryUsed the memory
+	// used to store it, including fragmentation and metadata.
+	OriginalDataSize   int64 `json:"original_data_size"`
+	CompressedDataSize int64 `json:"compressed_data_size"`
+	MemoryUsed         int64 `json:"memory_used"`
+}
+
+// CompressionRatio returns how many times the data is smaller once
+// compressed, or zero when the device is empty.
+func (z ZramResult) CompressionRatio() float64 {
+	if z.CompressedDataSize == 0 {
+		return 0
+	}
+	return float64(z.OriginalDataSize) / float64(z.CompressedDataSize)
 }
 
 func (m MemoryResult) UsedMemoryStr() string {
@@ -24,20 +96,316 @@ func (m MemoryResult) TotalMemoryStr() string {
 	return ByteCountIEC(m.TotalMemory)
 }
 
+func (m MemoryResult) UsedSwapStr() string {
+	return ByteCountIEC(m.UsedSwap)
+}
+
+func (m MemoryResult) TotalSwapStr() string {
+	return ByteCountIEC(m.TotalSwap)
+}
+
 func (m MemoryResult) MemoryUsagePercentageStr() string {
 	return fmt.Sprintf("%.2f%%", m.MemoryUsagePercentage)
 }
 
+// LoadResult is the system load average, read from /proc/loadavg, and
+// the run queue, read from /proc/stat.
+type LoadResult struct {
+	Load1        float64 `json:"load1"`
+	Load5        float64 `json:"load5"`
+	Load15       float64 `json:"load15"`
+	ProcsRunning int64   `json:"procs_running"`
+	ProcsBlocked int64   `json:"procs_blocked"`
+}
+
+// UptimeResult is the time since the system booted and the moment it
+// booted, as reported by the kernel.
+type UptimeResult struct {
+	UptimeSeconds int64     `json:"uptime_seconds"`
+	BootTime      time.Time `json:"boot_time"`
+	// BootID is the random ID the kernel generates on every boot.
+	BootID string `json:"boot_id,omitempty"`
+}
+
+// Uptime returns the time since the system booted.
+func (u UptimeResult) Uptime() time.Duration {
+	return time.Duration(u.UptimeSeconds) * time.Second
+}
+
+// RebootedSince reports whether the system rebooted after prev. The
+// boot IDs are compared when both are known, otherwise the uptime going
+// backwards is taken as a reboot. The boot times aren't compared, as
+// they're derived from the current time and move with clock
+// adjustments, such as the first NTP sync on boards without a RTC.
+func (u UptimeResult) RebootedSince(prev UptimeResult) bool {
+	if u.BootID != "" && prev.BootID != "" {
+		return u.BootID != prev.BootID
+	}
+	if u.UptimeSeconds == 0 || prev.UptimeSeconds == 0 {
+		return false
+	}
+	return u.UptimeSeconds < prev.UptimeSeconds
+}
+
+const (
+	// EventReboot is recorded on the first measurement after the system
+	// booted, at the boot time.
+	EventReboot = "reboot"
+	// EventShutdown is recorded when the agent is stopped because the
+	// system is shutting down.
+	EventShutdown = "shutdown"
+)
+
+// EventResult is something that happened to the system at Timestamp,
+// rather than a measurement.
+type EventResult struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	// Unexpected is set for reboots not preceded by a shutdown event,
+	// such as power losses, kernel panics or watchdog resets.
+	Unexpected bool `json:"unexpected,omitempty"`
+}
+
+// PressureResources are the resources reporting Pressure Stall
+// Information, in the order they're displayed.
+var PressureResources = []string{"cpu", "memory", "io"}
+
+// PressureResult is a line of the Pressure Stall Information of a
+// resource: the share of time some or all (full) tasks were stalled
+// waiting for it, averaged over 10s, 60s and 300s, and the total stall
+// time since boot.
+type PressureResult struct {
+	Resource          string  `json:"resource"`
+	Kind              string  `json:"kind"`
+	Avg10             float64 `json:"avg10"`
+	Avg60             float64 `json:"avg60"`
+	Avg300            float64 `json:"avg300"`
+	TotalMicroseconds int64   `json:"total_us"`
+}
+
 type TemperatureResult struct {
-	Temperature    float64 `json:"temperature"`
-	RawTemperature int64   `json:"raw_temperature"`
+	Temperature    float64                   `json:"temperature"`
+	RawTemperature int64                     `json:"raw_temperature"`
+	Sensors        []TemperatureSensorResult `json:"sensors,omitempty"`
+}
+
+// TemperatureSensorResult is the reading of a thermal zone, hwmon or
+// 1-Wire temperature sensor, in °C.
+type TemperatureSensorResult struct {
+	// Sensor identifies the sensor, such as "thermal_zone0",
+	// "cpu_thermal/temp1" or "w1/28-0316a2795bff".
+	Sensor string `json:"sensor"`
+	// Chip is the thermal zone type, the hwmon device name or
+	// "ds18b20".
+	Chip string `json:"chip"`
+	// Label is the hwmon sensor label or the friendly name configured
+	// for a 1-Wire sensor.
+	Label       string  `json:"label,omitempty"`
+	Temperature float64 `json:"temperature"`
+}
+
+// Name returns the chip and, when present, the label of the sensor.
+func (t TemperatureSensorResult) Name() string {
+	if t.Label == "" {
+		return t.Chip
+	}
+	return t.Chip + "/" + t.Label
+}
+
+// FilesystemResult is the space and inode usage of a mounted volume.
+type FilesystemResult struct {
+	Mountpoint            string  `json:"mountpoint"`
+	Device                string  `json:"device"`
+	FSType                string  `json:"fstype"`
+	Total                 int64   `json:"total"`
+	Used                  int64   `json:"used"`
+	Free                  int64   `json:"free"`
+	UsagePercentage       float64 `json:"usage_percentage"`
+	InodesTotal           int64   `json:"inodes_total"`
+	InodesUsed            int64   `json:"inodes_used"`
+	InodesFree            int64   `json:"inodes_free"`
+	InodesUsagePercentage float64 `json:"inodes_usage_percentage"`
+}
+
+func (f FilesystemResult) UsedStr() string {
+	return ByteCountIEC(f.Used)
+}
+
+func (f FilesystemResult) TotalStr() string {
+	return ByteCountIEC(f.Total)
+}
+
+// DiskIOResult is the activity of a block device during the sampling
+// window, computed from /proc/diskstats.
+type DiskIOResult struct {
+	Device                string  `json:"device"`
+	ReadBytesPerSecond    float64 `json:"read_bytes_per_second"`
+	WriteBytesPerSecond   float64 `json:"write_bytes_per_second"`
+	ReadIOPS              float64 `json:"read_iops"`
+	WriteIOPS             float64 `json:"write_iops"`
+	AwaitMilliseconds     float64 `json:"await_ms"`
+	UtilizationPercentage float64 `json:"utilization_percentage"`
+}
+
+// NetworkResult is the traffic of a network interface during the
+// sampling window and its link state.
+type NetworkResult struct {
+	Interface string `json:"interface"`
+	Up        bool   `json:"up"`
+	// SpeedMbps is the link speed, zero when the driver doesn't report
+	// it, as for wireless interfaces.
+	SpeedMbps          int64   `json:"speed_mbps"`
+	RxBytesPerSecond   float64 `json:"rx_bytes_per_second"`
+	TxBytesPerSecond   float64 `json:"tx_bytes_per_second"`
+	RxPacketsPerSecond float64 `json:"rx_packets_per_second"`
+	TxPacketsPerSecond float64 `json:"tx_packets_per_second"`
+	RxErrorsPerSecond  float64 `json:"rx_errors_per_second"`
+	TxErrorsPerSecond  float64 `json:"tx_errors_per_second"`
+	RxDropsPerSecond   float64 `json:"rx_drops_per_second"`
+	TxDropsPerSecond   float64 `json:"tx_drops_per_second"`
+}
+
+// WirelessResult is the link quality of a wireless interface, read
+// from /proc/net/wireless and, when available, `iw dev <if> link`.
+type WirelessResult struct {
+	Interface   string  `json:"interface"`
+	SSID        string  `json:"ssid,omitempty"`
+	LinkQuality float64 `json:"link_quality"`
+	SignalDBm   float64 `json:"signal_dbm"`
+	// NoiseDBm is zero when the driver doesn't report it.
+	NoiseDBm      float64 `json:"noise_dbm,omitempty"`
+	RxBitrateMbps float64 `json:"rx_bitrate_mbps,omitempty"`
+	TxBitrateMbps float64 `json:"tx_bitrate_mbps,omitempty"`
+}
+
+// ProcessResult is the resource usage of a single process. Its CPU
+// usage is measured during the sampling window and, as in top, may be
+// above 100% for multithreaded processes.
+type ProcessResult struct {
+	PID           int32   `json:"pid"`
+	Name          string  `json:"name"`
+	Cmdline       string  `json:"cmdline,omitempty"`
+	User          string  `json:"user,omitempty"`
+	CPUPercentage float64 `json:"cpu_percentage"`
+	RSS           int64   `json:"rss"`
+	Threads       int32   `json:"threads"`
+}
+
+// ProcessesResult holds the Limit processes using the most CPU and the
+// Limit processes using the most resident memory. Processes present in
+// both rankings are listed once.
+type ProcessesResult struct {
+	Limit     int             `json:"limit"`
+	Processes []ProcessResult `json:"processes"`
+}
+
+// TopCPU returns the processes using the most CPU, busiest first.
+func (p ProcessesResult) TopCPU() []ProcessResult {
+	return p.top(func(a, b ProcessResult) int {
+		return cmp.Compare(b.CPUPercentage, a.CPUPercentage)
+	})
+}
+
+// TopMemory returns the processes using the most resident memory,
+// largest first.
+func (p ProcessesResult) TopMemory() []ProcessResult {
+	return p.top(func(a, b ProcessResult) int {
+		return cmp.Compare(b.RSS, a.RSS)
+	})
+}
+
+func (p ProcessesResult) top(compare func(a, b ProcessResult) int) []ProcessResult {
+	sorted := slices.SortedStableFunc(slices.Values(p.Processes), compare)
+	return sorted[:min(p.Limit, len(sorted))]
+}
+
+// ProcessesSnapshot is a ProcessesResult taken at Timestamp, as kept
+// by the processes store.
+type ProcessesSnapshot struct {
+	Timestamp time.Time `json:"timestamp"`
+	ProcessesResult
+}
+
+// ThrottlingFlags are the conditions reported by the Raspberry Pi
+// firmware with `vcgencmd get_throttled`.
+type ThrottlingFlags struct {
+	UnderVoltage         bool `json:"under_voltage"`
+	ArmFrequencyCapped   bool `json:"arm_frequency_capped"`
+	Throttled            bool `json:"throttled"`
+	SoftTemperatureLimit bool `json:"soft_temperature_limit"`
+}
+
+// ThrottlingConditions are the names of the ThrottlingFlags
+// conditions, as used by Get and Set.
+var ThrottlingConditions = []string{"under_voltage", "arm_frequency_capped", "throttled", "soft_temperature_limit"}
+
+// Get returns the flag of the named condition.
+func (f ThrottlingFlags) Get(condition string) bool {
+	if p := f.flag(condition); p != nil {
+		return *p
+	}
+	return false
+}
+
+// Set sets the flag of the named condition, ignoring unknown ones.
+func (f *ThrottlingFlags) Set(condition string, v bool) {
+	if p := f.flag(condition); p != nil {
+		*p = v
+	}
+}
+
+func (f *ThrottlingFlags) flag(condition string) *bool {
+	switch condition {
+	case "under_voltage":
+		return &f.UnderVoltage
+	case "arm_frequency_capped":
+		return &f.ArmFrequencyCapped
+	case "throttled":
+		return &f.Throttled
+	case "soft_temperature_limit":
+		return &f.SoftTemperatureLimit
+	}
+	return nil
+}
+
+// Any reports whether any of the conditions is set.
+func (f ThrottlingFlags) Any() bool {
+	return f.UnderVoltage || f.ArmFrequencyCapped || f.Throttled || f.SoftTemperatureLimit
+}
+
+// ThrottlingResult is the Raspberry Pi throttling state, both current
+// and since boot, and the voltage of each rail. It's only measured
+// where vcgencmd is available, as it ships with Raspberry Pi OS.
+type ThrottlingResult struct {
+	Now      ThrottlingFlags `json:"now"`
+	Occurred ThrottlingFlags `json:"occurred"`
+	Voltages []VoltageResult `json:"voltages,omitempty"`
+}
+
+// VoltageResult is the voltage of a rail read with `vcgencmd
+// measure_volts`.
+type VoltageResult struct {
+	Rail  string  `json:"rail"`
+	Volts float64 `json:"volts"`
 }
 
+// ProbesResult is a measurement of every probe at a given moment. Its
+// JSON representation is part of the agent's public interface (HTTP
+// API and command outputs), so fields must not be renamed.
 type ProbesResult struct {
-	CPU       CPUResult
-	Memory    MemoryResult
-	Temp      TemperatureResult
-	Timestamp time.Time
+	CPU         CPUResult          `json:"cpu"`
+	Memory      MemoryResult       `json:"memory"`
+	Load        LoadResult         `json:"load"`
+	Uptime      UptimeResult       `json:"uptime"`
+	Pressure    []PressureResult   `json:"pressure,omitempty"`
+	Temp        TemperatureResult  `json:"temperature"`
+	Throttling  *ThrottlingResult  `json:"throttling,omitempty"`
+	Filesystems []FilesystemResult `json:"filesystems,omitempty"`
+	DiskIO      []DiskIOResult     `json:"disk_io,omitempty"`
+	Network     []NetworkResult    `json:"network,omitempty"`
+	Wireless    []WirelessResult   `json:"wireless,omitempty"`
+	Processes   *ProcessesResult   `json:"processes,omitempty"`
+	Timestamp   time.Time          `json:"timestamp"`
 }
 
 // ByteCountIEC converts a byte count to a human-readable string using IEC (binary) units (base 1024).
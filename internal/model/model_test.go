@@ -0,0 +1,53 @@
+package model
+
+import "testing"
+
+func TestRebootedSince(t *testing.T) {
+	tests := []struct {
+		name string
+		prev UptimeResult
+		cur  UptimeResult
+		want bool
+	}{
+		{
+			name: "same boot id",
+			prev: UptimeResult{UptimeSeconds: 100, BootID: "a"},
+			cur:  UptimeResult{UptimeSeconds: 110, BootID: "a"},
+		},
+		{
+			name: "new boot id",
+			prev: UptimeResult{UptimeSeconds: 100, BootID: "a"},
+			cur:  UptimeResult{UptimeSeconds: 5000, BootID: "b"},
+			want: true,
+		},
+		{
+			// the uptime going backwards isn't a reboot when the boot id
+			// tells otherwise
+			name: "same boot id with a smaller uptime",
+			prev: UptimeResult{UptimeSeconds: 100, BootID: "a"},
+			cur:  UptimeResult{UptimeSeconds: 90, BootID: "a"},
+		},
+		{
+			name: "uptime going backwards without boot id",
+			prev: UptimeResult{UptimeSeconds: 100},
+			cur:  UptimeResult{UptimeSeconds: 10, BootID: "b"},
+			want: true,
+		},
+		{
+			name: "uptime going forward without boot id",
+			prev: UptimeResult{UptimeSeconds: 100},
+			cur:  UptimeResult{UptimeSeconds: 110},
+		},
+		{
+			name: "uptime not measured",
+			prev: UptimeResult{UptimeSeconds: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cur.RebootedSince(tt.prev); got != tt.want {
+				t.Errorf("RebootedSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,588 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
+	"github.com/prometheus/prometheus/model/labels"
+	"sigs.k8s.io/yaml"
+)
+
+type Format string
+
+const (
+	FormatTable      Format = "table"
+	FormatJSON       Format = "json"
+	FormatYAML       Format = "yaml"
+	FormatCSV        Format = "csv"
+	FormatPrometheus Format = "prometheus"
+)
+
+var (
+	Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatCSV, FormatPrometheus}
+
+	ErrUnknownFormat = errors.New("unknown output format")
+)
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// ParseFormat validates f as one of the supported Formats.
+func ParseFormat(f string) (Format, error) {
+	format := Format(strings.ToLower(f))
+	if !slices.Contains(Formats, format) {
+		return "", fmt.Errorf("%w '%s', expected one of %v", ErrUnknownFormat, f, Formats)
+	}
+	return format, nil
+}
+
+// Write prints results to w in the given format. When metrics isn't
+// empty only those metrics are printed, the json and yaml formats
+// leaving out the probes without any of them. The table and csv formats
+// also mark where events happened, explaining gaps in the measurements.
+func Write(w io.Writer, format Format, results []model.ProbesResult, metrics []string, events []model.EventResult) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(filterResults(results, metrics))
+	case FormatYAML:
+		b, err := yaml.Marshal(filterResults(results, metrics))
+		if err != nil {
+			return fmt.Errorf("encoding yaml: %w", err)
+		}
+		_, err = w.Write(b)
+		return err
+	case FormatCSV:
+		return writeCSV(w, results, metrics, events)
+	case FormatPrometheus:
+		return writePrometheus(w, results, metrics)
+	case FormatTable:
+		return writeTable(w, results, metrics, events)
+	}
+	return fmt.Errorf("%w '%s'", ErrUnknownFormat, format)
+}
+
+// filteredResult is a model.ProbesResult whose probes can be left out.
+type filteredResult struct {
+	CPU         *model.CPUResult         `json:"cpu,omitempty"`
+	Memory      *model.MemoryResult      `json:"memory,omitempty"`
+	Load        *model.LoadResult        `json:"load,omitempty"`
+	Uptime      *model.UptimeResult      `json:"uptime,omitempty"`
+	Pressure    []model.PressureResult   `json:"pressure,omitempty"`
+	Temp        *model.TemperatureResult `json:"temperature,omitempty"`
+	Throttling  *model.ThrottlingResult  `json:"throttling,omitempty"`
+	Filesystems []model.FilesystemResult `json:"filesystems,omitempty"`
+	DiskIO      []model.DiskIOResult     `json:"disk_io,omitempty"`
+	Network     []model.NetworkResult    `json:"network,omitempty"`
+	Wireless    []model.WirelessResult   `json:"wireless,omitempty"`
+	Processes   *model.ProcessesResult   `json:"processes,omitempty"`
+	Timestamp   time.Time                `json:"timestamp"`
+}
+
+// filterResults leaves out of results the probes without any of the
+// metrics. The processes aren't series, so they're only kept when no
+// metric is selected.
+func filterResults(results []model.ProbesResult, metrics []string) any {
+	if len(metrics) == 0 {
+		return results
+	}
+	filtered := make([]filteredResult, 0, len(results))
+	for _, r := range results {
+		f := filteredResult{Timestamp: r.Timestamp}
+		if selected(metrics, "cpu_count", "cpu_usage", "cpu_core_usage", "cpu_time", "cpu_frequency", "cpu_frequency_min", "cpu_frequency_max", "cpu_governor") {
+			f.CPU = &r.CPU
+		}
+		if selected(metrics, "memory_usage_percentage", "used_memory", "total_memory", "available_memory", "buffers_memory", "cached_memory", "shared_memory", "dirty_memory", "writeback_memory",
+			"used_swap", "total_swap", "swap_in_bytes_per_second", "swap_out_bytes_per_second",
+			"zram_disk_size", "zram_original_data_size", "zram_compressed_data_size", "zram_memory_used") {
+			f.Memory = &r.Memory
+		}
+		if selected(metrics, "load1", "load5", "load15", "procs_running", "procs_blocked") {
+			f.Load = &r.Load
+		}
+		if selected(metrics, "uptime", "boot_time", "boot_info") {
+			f.Uptime = &r.Uptime
+		}
+		if selected(metrics, "pressure_avg10", "pressure_avg60", "pressure_avg300", "pressure_total") {
+			f.Pressure = r.Pressure
+		}
+		if selected(metrics, "temperature", "raw_temperature", "sensor_temperature") {
+			f.Temp = &r.Temp
+		}
+		if selected(metrics, "throttling_now", "throttling_occurred", "voltage") {
+			f.Throttling = r.Throttling
+		}
+		if selected(metrics, "filesystem_total", "filesystem_used", "filesystem_free", "filesystem_usage_percentage",
+			"filesystem_inodes_total", "filesystem_inodes_used", "filesystem_inodes_free", "filesystem_inodes_usage_percentage") {
+			f.Filesystems = r.Filesystems
+		}
+		if selected(metrics, "disk_read_bytes_per_second", "disk_write_bytes_per_second", "disk_read_iops", "disk_write_iops", "disk_await", "disk_utilization") {
+			f.DiskIO = r.DiskIO
+		}
+		if selected(metrics, "network_up", "network_speed", "network_receive_bytes_per_second", "network_transmit_bytes_per_second", "network_receive_packets_per_second", "network_transmit_packets_per_second",
+			"network_receive_errors_per_second", "network_transmit_errors_per_second", "network_receive_drops_per_second", "network_transmit_drops_per_second") {
+			f.Network = r.Network
+		}
+		if selected(metrics, "wireless_info", "wireless_link_quality", "wireless_signal", "wireless_noise", "wireless_rx_bitrate", "wireless_tx_bitrate") {
+			f.Wireless = r.Wireless
+		}
+		filtered = append(filtered, f)
+	}
+	return filtered
+}
+
+func selected(metrics []string, metric ...string) bool {
+	if len(metrics) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(metric, func(m string) bool {
+		return slices.Contains(metrics, m)
+	})
+}
+
+// writeCSV prints one line per series sample, so the columns don't
+// change when new probes are added. Events are printed as the series
+// they're stored as, between the measurements around them.
+func writeCSV(w io.Writer, results []model.ProbesResult, metrics []string, events []model.EventResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"timestamp", "metric", "labels", "value"}); err != nil {
+		return err
+	}
+	writeEvent := func(e model.EventResult) error {
+		var lbls string
+		if e.Type == model.EventReboot {
+			lbls = "unexpected=" + strconv.FormatBool(e.Unexpected)
+		}
+		return cw.Write([]string{strconv.FormatInt(e.Timestamp.UnixMilli(), 10), e.Type, lbls, "1"})
+	}
+	for _, r := range results {
+		for len(events) > 0 && !events[0].Timestamp.After(r.Timestamp) {
+			if err := writeEvent(events[0]); err != nil {
+				return err
+			}
+			events = events[1:]
+		}
+		ts := strconv.FormatInt(r.Timestamp.UnixMilli(), 10)
+		for _, s := range persistence.Samples(&r) {
+			if !selected(metrics, s.Name()) {
+				continue
+			}
+			var lbls []string
+			s.Labels.Range(func(l labels.Label) {
+				if l.Name != persistence.DimensionLabelName {
+					lbls = append(lbls, l.Name+"="+l.Value)
+				}
+			})
+			if err := cw.Write([]string{ts, s.Name(), strings.Join(lbls, ";"), strconv.FormatFloat(s.Value, 'f', -1, 64)}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range events {
+		if err := writeEvent(e); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writePrometheus prints the samples in the Prometheus text exposition
+// format, with their timestamps, grouped by metric.
+func writePrometheus(w io.Writer, results []model.ProbesResult, metrics []string) error {
+	var names []string
+	lines := make(map[string][]string)
+	for _, r := range results {
+		ts := r.Timestamp.UnixMilli()
+		for _, s := range persistence.Samples(&r) {
+			name := s.Name()
+			if !selected(metrics, name) {
+				continue
+			}
+			if _, ok := lines[name]; !ok {
+				names = append(names, name)
+			}
+			var lbls []string
+			s.Labels.Range(func(l labels.Label) {
+				if l.Name != persistence.DimensionLabelName {
+					lbls = append(lbls, fmt.Sprintf(`%s="%s"`, l.Name, labelValueEscaper.Replace(l.Value)))
+				}
+			})
+			var lblStr string
+			if len(lbls) > 0 {
+				lblStr = "{" + strings.Join(lbls, ",") + "}"
+			}
+			lines[name] = append(lines[name], fmt.Sprintf("%s%s %s %d", name, lblStr, strconv.FormatFloat(s.Value, 'g', -1, 64), ts))
+		}
+	}
+
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "# TYPE %s gauge\n", name); err != nil {
+			return err
+		}
+		for _, l := range lines[name] {
+			if _, err := fmt.Fprintln(w, l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, results []model.ProbesResult, metrics []string, events []model.EventResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for _, result := range results {
+		// events are marked between the measurements around them
+		for len(events) > 0 && !events[0].Timestamp.After(result.Timestamp) {
+			fmt.Fprintf(tw, "=== %s ===\n", EventStr(events[0]))
+			events = events[1:]
+		}
+		fmt.Fprintln(tw, "---")
+		fmt.Fprintf(tw, "Timestamp:\t%s\n", result.Timestamp.Format("2006-01-02 15:04:05"))
+		if selected(metrics, "cpu_count") {
+			fmt.Fprintf(tw, "CPU Count:\t%d\n", result.CPU.CPUCount)
+		}
+		if selected(metrics, "cpu_usage") {
+			fmt.Fprintf(tw, "CPU Usage:\t%.2f%%\n", result.CPU.CPUUsage)
+		}
+		if selected(metrics, "cpu_core_usage") {
+			for _, c := range result.CPU.Cores {
+				fmt.Fprintf(tw, "CPU Core %d Usage:\t%.2f%%\n", c.Core, c.Usage)
+			}
+		}
+		if selected(metrics, "cpu_frequency", "cpu_frequency_min", "cpu_frequency_max", "cpu_governor") {
+			for _, f := range result.CPU.Frequencies {
+				fmt.Fprintf(tw, "CPU Core %d Frequency:\t%d MHz (%d-%d MHz", f.Core, f.Current/1e6, f.Min/1e6, f.Max/1e6)
+				if f.Governor != "" {
+					fmt.Fprintf(tw, ", %s", f.Governor)
+				}
+				fmt.Fprintln(tw, ")")
+			}
+		}
+		if selected(metrics, "cpu_time") {
+			for _, t := range result.CPU.Times {
+				fmt.Fprintf(tw, "CPU Time (%s):\t%.2f%%\n", t.Mode, t.Percentage)
+			}
+		}
+		if selected(metrics, "memory_usage_percentage", "used_memory", "total_memory") {
+			fmt.Fprintf(tw, "Memory Usage:\t%d/%d (%.2f%%)\n", result.Memory.UsedMemory, result.Memory.TotalMemory, result.Memory.MemoryUsagePercentage)
+			fmt.Fprintf(tw, "Memory Usage (h):\t%s/%s (%.2f%%)\n", result.Memory.UsedMemoryStr(), result.Memory.TotalMemoryStr(), result.Memory.MemoryUsagePercentage)
+		}
+		if selected(metrics, "available_memory", "buffers_memory", "cached_memory", "shared_memory") {
+			fmt.Fprintf(tw, "Memory Breakdown (h):\tavailable %s, buffers %s, cached %s, shared %s\n",
+				model.ByteCountIEC(result.Memory.AvailableMemory),
+				model.ByteCountIEC(result.Memory.BuffersMemory),
+				model.ByteCountIEC(result.Memory.CachedMemory),
+				model.ByteCountIEC(result.Memory.SharedMemory),
+			)
+		}
+		if selected(metrics, "dirty_memory", "writeback_memory") {
+			fmt.Fprintf(tw, "Memory Dirty/Writeback (h):\t%s/%s\n", model.ByteCountIEC(result.Memory.DirtyMemory), model.ByteCountIEC(result.Memory.WritebackMemory))
+		}
+		if selected(metrics, "used_swap", "total_swap") {
+			fmt.Fprintf(tw, "Swap Usage (h):\t%s/%s\n", result.Memory.UsedSwapStr(), result.Memory.TotalSwapStr())
+		}
+		if selected(metrics, "swap_in_bytes_per_second", "swap_out_bytes_per_second") {
+			fmt.Fprintf(tw, "Swap I/O:\tin %s/s, out %s/s\n", model.ByteCountIEC(int64(result.Memory.SwapInBytesPerSecond)), model.ByteCountIEC(int64(result.Memory.SwapOutBytesPerSecond)))
+		}
+		if selected(metrics, "zram_disk_size", "zram_original_data_size", "zram_compressed_data_size", "zram_memory_used") {
+			for _, z := range result.Memory.Zram {
+				fmt.Fprintf(tw, "Zram %s:\t%s stored in %s (ratio %.2f), %s of memory, %s disk size\n",
+					z.Device,
+					model.ByteCountIEC(z.OriginalDataSize), model.ByteCountIEC(z.CompressedDataSize), z.CompressionRatio(),
+					model.ByteCountIEC(z.MemoryUsed), model.ByteCountIEC(z.DiskSize),
+				)
+			}
+		}
+		if selected(metrics, "load1", "load5", "load15") {
+			fmt.Fprintf(tw, "Load Average:\t%.2f %.2f %.2f", result.Load.Load1, result.Load.Load5, result.Load.Load15)
+			if result.CPU.CPUCount > 0 {
+				fmt.Fprintf(tw, " (%.2f per CPU)", result.Load.Load1/float64(result.CPU.CPUCount))
+			}
+			fmt.Fprintln(tw)
+		}
+		if selected(metrics, "uptime") && result.Uptime.UptimeSeconds > 0 {
+			fmt.Fprintf(tw, "Uptime:\t%s\n", result.Uptime.Uptime())
+		}
+		if selected(metrics, "boot_time") && !result.Uptime.BootTime.IsZero() {
+			fmt.Fprintf(tw, "Boot Time:\t%s\n", result.Uptime.BootTime.Format("2006-01-02 15:04:05"))
+		}
+		if selected(metrics, "procs_running", "procs_blocked") {
+			fmt.Fprintf(tw, "Processes:\t%d running, %d blocked\n", result.Load.ProcsRunning, result.Load.ProcsBlocked)
+		}
+		if selected(metrics, "pressure_avg10", "pressure_avg60", "pressure_avg300", "pressure_total") {
+			for _, p := range result.Pressure {
+				fmt.Fprintf(tw, "Pressure %s (%s):\t%.2f%% %.2f%% %.2f%%, %s stalled\n",
+					p.Resource, p.Kind, p.Avg10, p.Avg60, p.Avg300, time.Duration(p.TotalMicroseconds)*time.Microsecond)
+			}
+		}
+		if selected(metrics, "temperature", "raw_temperature") {
+			fmt.Fprintf(tw, "Temperature:\t%.2f°C\n", result.Temp.Temperature)
+		}
+		if selected(metrics, "sensor_temperature") {
+			for _, t := range result.Temp.Sensors {
+				fmt.Fprintf(tw, "Temperature (%s, %s):\t%.2f°C\n", t.Sensor, t.Name(), t.Temperature)
+			}
+		}
+		if selected(metrics, "filesystem_total", "filesystem_used", "filesystem_free", "filesystem_usage_percentage") {
+			for _, f := range result.Filesystems {
+				fmt.Fprintf(tw, "Filesystem %s:\t%s/%s (%.2f%%)\n", f.Mountpoint, f.UsedStr(), f.TotalStr(), f.UsagePercentage)
+			}
+		}
+		if selected(metrics, "filesystem_inodes_total", "filesystem_inodes_used", "filesystem_inodes_free", "filesystem_inodes_usage_percentage") {
+			for _, f := range result.Filesystems {
+				fmt.Fprintf(tw, "Filesystem %s Inodes:\t%d/%d (%.2f%%)\n", f.Mountpoint, f.InodesUsed, f.InodesTotal, f.InodesUsagePercentage)
+			}
+		}
+		if selected(metrics, "disk_read_bytes_per_second", "disk_write_bytes_per_second", "disk_read_iops", "disk_write_iops", "disk_await", "disk_utilization") {
+			for _, d := range result.DiskIO {
+				fmt.Fprintf(tw, "Disk %s I/O:\tread %s/s (%.1f IOPS), write %s/s (%.1f IOPS), await %.2f ms, util %.2f%%\n",
+					d.Device,
+					model.ByteCountIEC(int64(d.ReadBytesPerSecond)), d.ReadIOPS,
+					model.ByteCountIEC(int64(d.WriteBytesPerSecond)), d.WriteIOPS,
+					d.AwaitMilliseconds, d.UtilizationPercentage,
+				)
+			}
+		}
+		if selected(metrics, "network_up", "network_speed", "network_receive_bytes_per_second", "network_transmit_bytes_per_second", "network_receive_packets_per_second", "network_transmit_packets_per_second") {
+			for _, n := range result.Network {
+				fmt.Fprintf(tw, "Network %s:\t%s, rx %s/s (%.1f pkt/s), tx %s/s (%.1f pkt/s)\n",
+					n.Interface, linkStr(n),
+					model.ByteCountIEC(int64(n.RxBytesPerSecond)), n.RxPacketsPerSecond,
+					model.ByteCountIEC(int64(n.TxBytesPerSecond)), n.TxPacketsPerSecond,
+				)
+			}
+		}
+		if selected(metrics, "network_receive_errors_per_second", "network_transmit_errors_per_second", "network_receive_drops_per_second", "network_transmit_drops_per_second") {
+			for _, n := range result.Network {
+				fmt.Fprintf(tw, "Network %s Errors:\trx %.2f/s, tx %.2f/s, drops rx %.2f/s, tx %.2f/s\n",
+					n.Interface, n.RxErrorsPerSecond, n.TxErrorsPerSecond, n.RxDropsPerSecond, n.TxDropsPerSecond)
+			}
+		}
+		if selected(metrics, "wireless_info", "wireless_link_quality", "wireless_signal", "wireless_noise", "wireless_rx_bitrate", "wireless_tx_bitrate") {
+			for _, w := range result.Wireless {
+				fmt.Fprintf(tw, "Wi-Fi %s:\t%s\n", w.Interface, wirelessStr(w))
+			}
+		}
+		if result.Processes != nil && len(metrics) == 0 {
+			for _, p := range result.Processes.TopCPU() {
+				fmt.Fprintf(tw, "Top CPU Process:\t%s (pid %d) %.1f%%\n", p.Name, p.PID, p.CPUPercentage)
+			}
+			for _, p := range result.Processes.TopMemory() {
+				fmt.Fprintf(tw, "Top Memory Process:\t%s (pid %d) %s\n", p.Name, p.PID, model.ByteCountIEC(p.RSS))
+			}
+		}
+		if result.Throttling != nil && selected(metrics, "throttling_now", "throttling_occurred") {
+			fmt.Fprintf(tw, "Throttling (now):\t%s\n", throttlingStr(result.Throttling.Now))
+			fmt.Fprintf(tw, "Throttling (since boot):\t%s\n", throttlingStr(result.Throttling.Occurred))
+		}
+		if result.Throttling != nil && selected(metrics, "voltage") {
+			for _, v := range result.Throttling.Voltages {
+				fmt.Fprintf(tw, "Voltage (%s):\t%.4f V\n", v.Rail, v.Volts)
+			}
+		}
+	}
+	for _, e := range events {
+		fmt.Fprintf(tw, "=== %s ===\n", EventStr(e))
+	}
+	return tw.Flush()
+}
+
+// EventStr describes e and when it happened.
+func EventStr(e model.EventResult) string {
+	kind := e.Type
+	if e.Unexpected {
+		kind = "unexpected " + kind
+	}
+	return fmt.Sprintf("%s at %s", kind, e.Timestamp.Format("2006-01-02 15:04:05"))
+}
+
+// linkStr describes the link state and speed of n.
+func linkStr(n model.NetworkResult) string {
+	state := "down"
+	if n.Up {
+		state = "up"
+	}
+	if n.SpeedMbps > 0 {
+		return fmt.Sprintf("%s %d Mb/s", state, n.SpeedMbps)
+	}
+	return state
+}
+
+// wirelessStr describes the link of w, leaving out what the driver or
+// iw didn't report.
+func wirelessStr(w model.WirelessResult) string {
+	var parts []string
+	if w.SSID != "" {
+		parts = append(parts, fmt.Sprintf("SSID %q", w.SSID))
+	}
+	parts = append(parts, fmt.Sprintf("quality %.0f", w.LinkQuality), fmt.Sprintf("signal %.0f dBm", w.SignalDBm))
+	if w.NoiseDBm != 0 {
+		parts = append(parts, fmt.Sprintf("noise %.0f dBm", w.NoiseDBm))
+	}
+	if w.RxBitrateMbps != 0 || w.TxBitrateMbps != 0 {
+		parts = append(parts, fmt.Sprintf("bitrate rx %.1f/tx %.1f Mb/s", w.RxBitrateMbps, w.TxBitrateMbps))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// throttlingStr lists the conditions set in f.
+func throttlingStr(f model.ThrottlingFlags) string {
+	var set []string
+	for _, c := range model.ThrottlingConditions {
+		if f.Get(c) {
+			set = append(set, c)
+		}
+	}
+	if len(set) == 0 {
+		return "none"
+	}
+	return strings.Join(set, ", ")
+}
+
+// WriteProcesses prints a processes snapshot to w in the given format.
+// The prometheus format isn't supported as processes aren't series.
+func WriteProcesses(w io.Writer, format Format, snapshot *model.ProcessesSnapshot) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(snapshot)
+	case FormatYAML:
+		b, err := yaml.Marshal(snapshot)
+		if err != nil {
+			return fmt.Errorf("encoding yaml: %w", err)
+		}
+		_, err = w.Write(b)
+		return err
+	case FormatCSV:
+		return writeProcessesCSV(w, snapshot)
+	case FormatTable:
+		return writeProcessesTable(w, snapshot)
+	}
+	return fmt.Errorf("%w '%s' for processes", ErrUnknownFormat, format)
+}
+
+// writeProcessesCSV prints one line per process of each ranking.
+func writeProcessesCSV(w io.Writer, snapshot *model.ProcessesSnapshot) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"timestamp", "ranking", "rank", "pid", "user", "cpu_percentage", "rss", "threads", "name", "cmdline"}); err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(snapshot.Timestamp.UnixMilli(), 10)
+	rankings := []struct {
+		name      string
+		processes []model.ProcessResult
+	}{
+		{"cpu", snapshot.TopCPU()},
+		{"memory", snapshot.TopMemory()},
+	}
+	for _, r := range rankings {
+		for i, p := range r.processes {
+			err := cw.Write([]string{
+				ts, r.name, strconv.Itoa(i + 1), strconv.Itoa(int(p.PID)), p.User,
+				strconv.FormatFloat(p.CPUPercentage, 'f', 2, 64), strconv.FormatInt(p.RSS, 10),
+				strconv.Itoa(int(p.Threads)), p.Name, p.Cmdline,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeProcessesTable(w io.Writer, snapshot *model.ProcessesSnapshot) error {
+	fmt.Fprintf(w, "Timestamp: %s\n", snapshot.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(w, "\nTop CPU:")
+	if err := writeProcessList(w, snapshot.TopCPU()); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nTop Memory:")
+	return writeProcessList(w, snapshot.TopMemory())
+}
+
+func writeProcessList(w io.Writer, processes []model.ProcessResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PID\tUSER\tCPU%\tRSS\tTHREADS\tCOMMAND")
+	for _, p := range processes {
+		command := p.Cmdline
+		if command == "" {
+			command = p.Name
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%.1f\t%s\t%d\t%s\n", p.PID, p.User, p.CPUPercentage, model.ByteCountIEC(p.RSS), p.Threads, command)
+	}
+	return tw.Flush()
+}
+
+// WriteEvents prints events to w in the given format. The prometheus
+// format isn't supported, events are only queryable as series.
+func WriteEvents(w io.Writer, format Format, events []model.EventResult) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(events)
+	case FormatYAML:
+		b, err := yaml.Marshal(events)
+		if err != nil {
+			return fmt.Errorf("encoding yaml: %w", err)
+		}
+		_, err = w.Write(b)
+		return err
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"timestamp", "type", "unexpected"}); err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := cw.Write([]string{strconv.FormatInt(e.Timestamp.UnixMilli(), 10), e.Type, strconv.FormatBool(e.Unexpected)}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TIMESTAMP\tEVENT\tUNEXPECTED")
+		for _, e := range events {
+			fmt.Fprintf(tw, "%s\t%s\t%t\n", e.Timestamp.Format("2006-01-02 15:04:05"), e.Type, e.Unexpected)
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("%w '%s' for events", ErrUnknownFormat, format)
+}
+
+// WriteQuery prints the result of a PromQL query to w in the given
+// format, the table format being the PromQL text representation. The
+// csv and prometheus formats aren't supported.
+func WriteQuery(w io.Writer, format Format, result *persistence.QueryResult) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	case FormatYAML:
+		b, err := yaml.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("encoding yaml: %w", err)
+		}
+		_, err = w.Write(b)
+		return err
+	case FormatTable:
+		_, err := fmt.Fprintln(w, result.Result.String())
+		return err
+	}
+	return fmt.Errorf("%w '%s' for queries", ErrUnknownFormat, format)
+}
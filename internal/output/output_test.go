@@ -0,0 +1,89 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+func TestWriteCSVEvents(t *testing.T) {
+	start := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	results := []model.ProbesResult{
+		{Timestamp: start},
+		{Timestamp: start.Add(time.Hour)},
+	}
+	events := []model.EventResult{
+		{Type: model.EventShutdown, Timestamp: start.Add(10 * time.Minute)},
+		{Type: model.EventReboot, Timestamp: start.Add(20 * time.Minute)},
+		{Type: model.EventReboot, Timestamp: start.Add(2 * time.Hour), Unexpected: true},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, results, []string{"cpu_usage"}, events); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, r := range records[1:] {
+		got = append(got, r[1])
+	}
+	want := []string{"cpu_usage", model.EventShutdown, model.EventReboot, "cpu_usage", model.EventReboot}
+	if !slices.Equal(got, want) {
+		t.Errorf("csv metrics = %v, want %v", got, want)
+	}
+
+	last := records[len(records)-1]
+	if ts := strconv.FormatInt(events[2].Timestamp.UnixMilli(), 10); last[0] != ts || last[2] != "unexpected=true" {
+		t.Errorf("last csv line = %v, want the unexpected reboot at %s", last, ts)
+	}
+}
+
+func TestWriteJSONMetrics(t *testing.T) {
+	results := []model.ProbesResult{{
+		CPU:        model.CPUResult{CPUUsage: 12.5},
+		Throttling: &model.ThrottlingResult{},
+		Network:    []model.NetworkResult{{Interface: "eth0", Up: true}},
+		Timestamp:  time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC),
+	}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, results, []string{"cpu_usage", "sensor_temperature"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d results, want 1", len(got))
+	}
+	keys := slices.Sorted(maps.Keys(got[0]))
+	if want := []string{"cpu", "temperature", "timestamp"}; !slices.Equal(keys, want) {
+		t.Errorf("got probes %v, want %v", keys, want)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, FormatJSON, results, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"cpu", "memory", "load", "uptime", "temperature", "throttling", "network"} {
+		if _, ok := got[0][k]; !ok {
+			t.Errorf("missing %s without selected metrics", k)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+const (
+	bootStateFile = "boot.json"
+)
+
+// BootState is the boot seen by the last measurement, kept in a small
+// file beside the TSDB so telling a reboot apart doesn't need to scan
+// the uptime series.
+type BootState struct {
+	Uptime model.UptimeResult `json:"uptime"`
+	// Timestamp is when the state was written, the shutdown of this
+	// boot can only be recorded after it.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// BootStatePath returns the location of the boot state file, inside
+// the configured storage directory.
+func BootStatePath() string {
+	return filepath.Join(config.GetStoragePath(), bootStateFile)
+}
+
+// ReadBootState reads the state at path. The boolean is false when it
+// wasn't written yet.
+func ReadBootState(path string) (BootState, bool, error) {
+	var state BootState
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, false, nil
+	}
+	if err != nil {
+		return state, false, fmt.Errorf("reading boot state: %w", err)
+	}
+	if err := json.Unmarshal(b, &state); err != nil {
+		return state, false, fmt.Errorf("decoding boot state: %w", err)
+	}
+	return state, true, nil
+}
+
+// WriteBootState replaces the state at path.
+func WriteBootState(path string, state BootState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encoding boot state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating boot state directory: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0o644); err != nil {
+		return fmt.Errorf("writing boot state: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replacing boot state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+func TestBootState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage", bootStateFile)
+
+	if _, ok, err := ReadBootState(path); ok || err != nil {
+		t.Fatalf("ReadBootState() before writing = %v, %v, want no state", ok, err)
+	}
+
+	state := BootState{
+		Uptime: model.UptimeResult{
+			UptimeSeconds: 3600,
+			BootTime:      time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC),
+			BootID:        "2b8b5ab4-6d43-4a4c-9f2b-3c1d1f1e4c55",
+		},
+		Timestamp: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+	}
+	if err := WriteBootState(path, state); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := ReadBootState(path)
+	if err != nil || !ok {
+		t.Fatalf("ReadBootState() = %v, %v", ok, err)
+	}
+	if !got.Timestamp.Equal(state.Timestamp) || !got.Uptime.BootTime.Equal(state.Uptime.BootTime) ||
+		got.Uptime.BootID != state.Uptime.BootID || got.Uptime.UptimeSeconds != state.Uptime.UptimeSeconds {
+		t.Errorf("ReadBootState() = %+v, want %+v", got, state)
+	}
+}
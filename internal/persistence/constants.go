@@ -1,25 +1,130 @@
 package persistence
 
 const (
-	dimensionLabelName = "dimension"
+	DimensionLabelName  = "dimension"
+	unitLabelName       = "unit"
+	coreLabelName       = "core"
+	modeLabelName       = "mode"
+	governorLabelName   = "governor"
+	conditionLabelName  = "condition"
+	railLabelName       = "rail"
+	sensorLabelName     = "sensor"
+	chipLabelName       = "chip"
+	labelLabelName      = "label"
+	mountpointLabelName = "mountpoint"
+	deviceLabelName     = "device"
+	fstypeLabelName     = "fstype"
+	interfaceLabelName  = "interface"
+	ssidLabelName       = "ssid"
+	resourceLabelName   = "resource"
+	kindLabelName       = "kind"
+	unexpectedLabelName = "unexpected"
+	bootIDLabelName     = "boot_id"
 
 	memoryUsagePercentage = "memory_usage_percentage"
 	usedMemory            = "used_memory"
 	totalMemory           = "total_memory"
+	availableMemory       = "available_memory"
+	buffersMemory         = "buffers_memory"
+	cachedMemory          = "cached_memory"
+	sharedMemory          = "shared_memory"
+	dirtyMemory           = "dirty_memory"
+	writebackMemory       = "writeback_memory"
 
-	cpuCount = "cpu_count"
-	cpuUsage = "cpu_usage"
+	usedSwap    = "used_swap"
+	totalSwap   = "total_swap"
+	swapInRate  = "swap_in_bytes_per_second"
+	swapOutRate = "swap_out_bytes_per_second"
+
+	zramDiskSize           = "zram_disk_size"
+	zramOriginalDataSize   = "zram_original_data_size"
+	zramCompressedDataSize = "zram_compressed_data_size"
+	zramMemoryUsed         = "zram_memory_used"
+
+	cpuCount     = "cpu_count"
+	cpuUsage     = "cpu_usage"
+	cpuCoreUsage = "cpu_core_usage"
+	cpuTime      = "cpu_time"
+
+	cpuFrequency    = "cpu_frequency"
+	cpuFrequencyMin = "cpu_frequency_min"
+	cpuFrequencyMax = "cpu_frequency_max"
+	cpuGovernor     = "cpu_governor"
+
+	load1        = "load1"
+	load5        = "load5"
+	load15       = "load15"
+	procsRunning = "procs_running"
+	procsBlocked = "procs_blocked"
+
+	uptime   = "uptime"
+	bootTime = "boot_time"
+	bootInfo = "boot_info"
+
+	eventReboot   = "reboot"
+	eventShutdown = "shutdown"
+
+	pressureAvg10  = "pressure_avg10"
+	pressureAvg60  = "pressure_avg60"
+	pressureAvg300 = "pressure_avg300"
+	pressureTotal  = "pressure_total"
 
 	temperature    = "temperature"
 	rawTemperature = "raw_temperature"
 
-	memoryUsagePercentageIdx = 0
-	usedMemoryIdx            = 1
-	totalMemoryIdx           = 2
+	sensorTemperature = "sensor_temperature"
+
+	throttlingNow      = "throttling_now"
+	throttlingOccurred = "throttling_occurred"
+	voltage            = "voltage"
+
+	filesystemTotal                 = "filesystem_total"
+	filesystemUsed                  = "filesystem_used"
+	filesystemFree                  = "filesystem_free"
+	filesystemUsagePercentage       = "filesystem_usage_percentage"
+	filesystemInodesTotal           = "filesystem_inodes_total"
+	filesystemInodesUsed            = "filesystem_inodes_used"
+	filesystemInodesFree            = "filesystem_inodes_free"
+	filesystemInodesUsagePercentage = "filesystem_inodes_usage_percentage"
+
+	diskReadBytes   = "disk_read_bytes_per_second"
+	diskWriteBytes  = "disk_write_bytes_per_second"
+	diskReadIOPS    = "disk_read_iops"
+	diskWriteIOPS   = "disk_write_iops"
+	diskAwait       = "disk_await"
+	diskUtilization = "disk_utilization"
+
+	networkUp        = "network_up"
+	networkSpeed     = "network_speed"
+	networkRxBytes   = "network_receive_bytes_per_second"
+	networkTxBytes   = "network_transmit_bytes_per_second"
+	networkRxPackets = "network_receive_packets_per_second"
+	networkTxPackets = "network_transmit_packets_per_second"
+	networkRxErrors  = "network_receive_errors_per_second"
+	networkTxErrors  = "network_transmit_errors_per_second"
+	networkRxDrops   = "network_receive_drops_per_second"
+	networkTxDrops   = "network_transmit_drops_per_second"
+
+	wirelessInfo      = "wireless_info"
+	wirelessQuality   = "wireless_link_quality"
+	wirelessSignal    = "wireless_signal"
+	wirelessNoise     = "wireless_noise"
+	wirelessRxBitrate = "wireless_rx_bitrate"
+	wirelessTxBitrate = "wireless_tx_bitrate"
 
-	cpuCountIdx = 0
-	cpuUsageIdx = 1
+	unitPercent = "percent"
+	unitBytes   = "bytes"
+	unitCount   = "count"
+	unitCelsius = "celsius"
+	unitHertz   = "hertz"
+	unitVolts   = "volts"
 
-	temperatureIdx    = 0
-	rawTemperatureIdx = 1
+	unitBytesPerSecond = "bytes_per_second"
+	unitIOPS           = "iops"
+	unitMilliseconds   = "milliseconds"
+	unitPerSecond      = "per_second"
+	unitMbps           = "megabits_per_second"
+	unitDBm            = "dbm"
+	unitMicroseconds   = "microseconds"
+	unitSeconds        = "seconds"
 )
@@ -0,0 +1,89 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// eventsMatcher selects the series of the events, which are kept out of
+// the measurements returned by Store.Get.
+var eventsMatcher = labels.MustNewMatcher(labels.MatchRegexp, DimensionLabelName, eventReboot+"|"+eventShutdown)
+
+// eventSample flattens e into the series written to the database, an
+// event is a sample valued 1 at the moment it happened.
+func eventSample(e model.EventResult) Sample {
+	switch e.Type {
+	case model.EventReboot:
+		return newSample(1, DimensionLabelName, eventReboot, unexpectedLabelName, strconv.FormatBool(e.Unexpected))
+	default:
+		return newSample(1, DimensionLabelName, e.Type)
+	}
+}
+
+// PersistEvent writes e at its own timestamp, rather than at the time
+// of a measurement.
+func (s *Store) PersistEvent(ctx context.Context, e model.EventResult) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return ErrStoreClosed
+	}
+
+	smp := eventSample(e)
+	appender := s.db.Appender(ctx)
+	if _, err := appender.Append(0, smp.Labels, e.Timestamp.UnixMilli(), smp.Value); err != nil {
+		_ = appender.Rollback()
+		return fmt.Errorf("appending %s: %w", smp.Name(), err)
+	}
+	if err := appender.Commit(); err != nil {
+		return fmt.Errorf("committing %s: %w", smp.Name(), err)
+	}
+	return nil
+}
+
+// Events returns the events that happened between from and to, oldest
+// first. A zero time means unbounded.
+func (s *Store) Events(ctx context.Context, from, to time.Time) ([]model.EventResult, error) {
+	f := Filter{From: from, To: to}
+	if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return nil, ErrInvalidRange
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, ErrStoreClosed
+	}
+
+	var events []model.EventResult
+	mint, maxt := f.timeRange()
+	err := s.fetch(ctx, mint, maxt, func(lbls labels.Labels, ts int64, _ float64) {
+		unexpected, _ := strconv.ParseBool(lbls.Get(unexpectedLabelName))
+		events = append(events, model.EventResult{
+			Type:       lbls.Get(DimensionLabelName),
+			Timestamp:  time.UnixMilli(ts),
+			Unexpected: unexpected,
+		})
+	}, eventsMatcher)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(events, func(a, b model.EventResult) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return events, nil
+}
+
+// notMatcher returns a matcher selecting the series m doesn't.
+func notMatcher(m *labels.Matcher) *labels.Matcher {
+	return labels.MustNewMatcher(labels.MatchNotRegexp, m.Name, m.Value)
+}
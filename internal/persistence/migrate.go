@@ -0,0 +1,150 @@
+package persistence
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+)
+
+// secondsThreshold separates timestamps written in seconds by older
+// versions of the agent from millisecond ones: 1e11 seconds is in the
+// year 5138 while 1e11 milliseconds is in March 1973.
+const secondsThreshold = int64(1e11)
+
+type migrationSeries struct {
+	lbls    labels.Labels
+	samples map[int64]float64
+}
+
+// MigrateTimestamps rewrites the database at path converting samples
+// stored with second timestamps into milliseconds. The database must
+// not be in use. The original data is kept in a backup directory next
+// to path. It returns the number of converted samples.
+func MigrateTimestamps(ctx context.Context, path string) (int, error) {
+	// the blocks written in seconds look decades old, they'd be removed
+	// on open by the retention
+	store, err := Open(path, WithRetention(0))
+	if err != nil {
+		return 0, err
+	}
+
+	series := make(map[string]*migrationSeries)
+	converted := 0
+	err = store.fetch(ctx, math.MinInt64, math.MaxInt64, func(lbls labels.Labels, ts int64, v float64) {
+		s, ok := series[lbls.String()]
+		if !ok {
+			s = &migrationSeries{lbls: lbls, samples: make(map[int64]float64)}
+			series[lbls.String()] = s
+		}
+		if ts < secondsThreshold {
+			ts *= 1000
+			converted++
+			if _, exists := s.samples[ts]; exists {
+				// keep the sample already written in milliseconds
+				return
+			}
+		}
+		s.samples[ts] = v
+	}, labels.MustNewMatcher(labels.MatchRegexp, DimensionLabelName, ".+"))
+	if closeErr := store.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading samples: %w", err)
+	}
+
+	if converted == 0 {
+		return 0, nil
+	}
+
+	tmpPath := path + ".migration"
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return 0, fmt.Errorf("cleaning migration directory: %w", err)
+	}
+	if err := writeBlocks(ctx, tmpPath, series); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return 0, err
+	}
+
+	backupPath := fmt.Sprintf("%s.bak-%d", path, time.Now().Unix())
+	if err := os.Rename(path, backupPath); err != nil {
+		return 0, fmt.Errorf("backing up database: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, fmt.Errorf("replacing database: %w", err)
+	}
+
+	slog.With(
+		slog.String("path", path),
+		slog.String("backup", backupPath),
+		slog.Int("converted_samples", converted),
+	).InfoContext(ctx, "timestamps migrated")
+
+	return converted, nil
+}
+
+// migrationSample is a sample of a series being written by writeBlocks.
+type migrationSample struct {
+	lbls labels.Labels
+	ts   int64
+	v    float64
+}
+
+// writeBlocks writes series into dir as blocks aligned to the default
+// TSDB block duration, the same way promtool backfills data.
+func writeBlocks(ctx context.Context, dir string, series map[string]*migrationSeries) error {
+	blockDuration := tsdb.DefaultBlockDuration
+	blocks := make(map[int64][]migrationSample)
+	for _, s := range series {
+		for ts, v := range s.samples {
+			start := blockDuration * (ts / blockDuration)
+			blocks[start] = append(blocks[start], migrationSample{lbls: s.lbls, ts: ts, v: v})
+		}
+	}
+
+	for _, start := range slices.Sorted(maps.Keys(blocks)) {
+		samples := blocks[start]
+		// each series must be appended in time order
+		slices.SortFunc(samples, func(a, b migrationSample) int {
+			return cmp.Or(labels.Compare(a.lbls, b.lbls), cmp.Compare(a.ts, b.ts))
+		})
+		if err := writeBlock(ctx, dir, samples); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeBlock(ctx context.Context, dir string, samples []migrationSample) error {
+	w, err := tsdb.NewBlockWriter(&logger{l: slog.With("pkg", "persistence")}, dir, 2*tsdb.DefaultBlockDuration)
+	if err != nil {
+		return fmt.Errorf("creating block writer: %w", err)
+	}
+	defer func() {
+		_ = w.Close()
+	}()
+
+	appender := w.Appender(ctx)
+	for _, s := range samples {
+		if _, err := appender.Append(0, s.lbls, s.ts, s.v); err != nil {
+			_ = appender.Rollback()
+			return fmt.Errorf("appending %s: %w", s.lbls.String(), err)
+		}
+	}
+	if err := appender.Commit(); err != nil {
+		return fmt.Errorf("committing block samples: %w", err)
+	}
+	if _, err := w.Flush(ctx); err != nil {
+		return fmt.Errorf("flushing block: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+)
+
+// writeTestBlock writes the samples, by timestamp, of the series lbls
+// as a persisted block of the database at dir.
+func writeTestBlock(t *testing.T, dir string, lbls labels.Labels, samples map[int64]float64) {
+	t.Helper()
+	ctx := context.Background()
+	w, err := tsdb.NewBlockWriter(&logger{l: slog.Default()}, dir, 2*tsdb.DefaultBlockDuration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Close() }()
+	app := w.Appender(ctx)
+	for _, ts := range slices.Sorted(maps.Keys(samples)) {
+		if _, err := app.Append(0, lbls, ts, samples[ts]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := app.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrateTimestamps(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "tsdb")
+	lbls := labels.FromStrings(DimensionLabelName, temperature, unitLabelName, unitCelsius)
+
+	// an old version wrote seconds, the current one milliseconds, each in
+	// its own block
+	now := time.Now().Truncate(time.Minute)
+	seconds := map[int64]float64{
+		now.Add(-2 * time.Hour).Unix(): 40,
+		now.Add(-time.Hour).Unix():     41,
+	}
+	writeTestBlock(t, dir, lbls, seconds)
+	writeTestBlock(t, dir, lbls, map[int64]float64{now.UnixMilli(): 42})
+
+	converted, err := MigrateTimestamps(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted != len(seconds) {
+		t.Errorf("converted %d samples, want %d", converted, len(seconds))
+	}
+
+	store, err := Open(dir, WithRetention(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = store.Close() }()
+
+	got := make(map[int64]float64)
+	err = store.fetch(ctx, math.MinInt64, math.MaxInt64, func(_ labels.Labels, ts int64, v float64) {
+		got[ts] = v
+	}, labels.MustNewMatcher(labels.MatchEqual, DimensionLabelName, temperature))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int64]float64{
+		now.Add(-2 * time.Hour).UnixMilli(): 40,
+		now.Add(-time.Hour).UnixMilli():     41,
+		now.UnixMilli():                     42,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("samples after the migration = %v, want %v", got, want)
+	}
+	for ts, v := range want {
+		if got[ts] != v {
+			t.Errorf("sample at %d = %v, want %v", ts, got[ts], v)
+		}
+	}
+}
@@ -2,24 +2,43 @@ package persistence
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
+	"os"
+	"path/filepath"
+	"regexp"
 	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-kit/log"
 
+	"github.com/eldius/rpi-system-monitor/internal/config"
 	"github.com/eldius/rpi-system-monitor/internal/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/prometheus/prometheus/tsdb/chunkenc"
 )
 
+const (
+	tsdbDir = "tsdb"
+
+	// legacyDBPath is where older versions kept the database, relative
+	// to the working directory.
+	legacyDBPath = ".db/tsdb.db"
+)
+
 var (
 	_ log.Logger = &logger{}
 )
 
+var (
+	ErrStoreClosed = errors.New("store is closed")
+)
+
 type logger struct {
 	l *slog.Logger
 }
@@ -30,215 +49,287 @@ func (l *logger) Log(keyvals ...interface{}) error {
 	return nil
 }
 
-func openDB() (*tsdb.DB, error) {
-	db, err := tsdb.Open(".db/tsdb.db", &logger{l: slog.With("pkg", "persistence")}, nil, tsdb.DefaultOptions(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("opening database: %w", err)
-	}
-	return db, nil
+// Store keeps a single TSDB handle open for the whole process lifetime.
+type Store struct {
+	mu sync.RWMutex
+	db *tsdb.DB
 }
 
-func Persist(ctx context.Context, result *model.ProbesResult) error {
-	db, err := openDB()
-	if err != nil {
-		return fmt.Errorf("opening database: %w", err)
-	}
-	defer func() {
-		_ = db.Close()
-	}()
+// Path returns the location of the process wide database, inside the
+// configured storage directory.
+func Path() string {
+	return filepath.Join(config.GetStoragePath(), tsdbDir)
+}
 
-	if err := saveTemperature(ctx, db, result.Temp, result.Timestamp.Unix()); err != nil {
-		return fmt.Errorf("appending temperature: %w", err)
-	}
+// Option customizes the database options.
+type Option func(*tsdb.Options)
 
-	if err := saveMemoryUsage(ctx, db, result.Memory, result.Timestamp.Unix()); err != nil {
-		return fmt.Errorf("appending memory: %w", err)
+// WithRetention defines for how long samples are kept, zero keeps them
+// forever. Whole blocks are removed once they are older than d.
+func WithRetention(d time.Duration) Option {
+	return func(o *tsdb.Options) {
+		o.RetentionDuration = d.Milliseconds()
 	}
+}
 
-	if err := saveCPUUsage(ctx, db, result.CPU, result.Timestamp.Unix()); err != nil {
-		return fmt.Errorf("appending cpu usage: %w", err)
+// WithMaxBytes defines the maximum size of the database blocks, zero
+// means unlimited. The oldest blocks are removed first.
+func WithMaxBytes(n int64) Option {
+	return func(o *tsdb.Options) {
+		o.MaxBytes = n
 	}
-	return nil
 }
 
-func Get(ctx context.Context) ([]model.ProbesResult, error) {
-	db, err := openDB()
-	if err != nil {
-		return nil, fmt.Errorf("opening database: %w", err)
+// Open opens (or creates) the database at path.
+func Open(path string, opts ...Option) (*Store, error) {
+	o := tsdb.DefaultOptions()
+	for _, opt := range opts {
+		opt(o)
 	}
-	tempTS, err := getTemperature(ctx, db)
+	db, err := tsdb.Open(path, &logger{l: slog.With("pkg", "persistence")}, nil, o, nil)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("opening database: %w", err)
 	}
-	memTS, err := getMemoryUsage(ctx, db)
-	if err != nil {
-		return nil, err
+	return &Store{db: db}, nil
+}
+
+// Close flushes and closes the underlying database. It's safe to call
+// it more than once.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return nil
 	}
-	cpuTS, err := getCPUUsage(ctx, db)
+	err := s.db.Close()
+	s.db = nil
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("closing database: %w", err)
 	}
+	return nil
+}
 
-	var result []model.ProbesResult
-	for k, v := range tempTS {
-		result = append(result, model.ProbesResult{
-			Timestamp: time.Unix(k, 0),
-			Temp: model.TemperatureResult{
-				Temperature:    v[temperatureIdx],
-				RawTemperature: int64(v[rawTemperatureIdx]),
-			},
-			Memory: model.MemoryResult{
-				MemoryUsagePercentage: memTS[k][memoryUsagePercentageIdx],
-				UsedMemory:            int64(memTS[k][usedMemoryIdx]),
-				TotalMemory:           int64(memTS[k][totalMemoryIdx]),
-			},
-			CPU: model.CPUResult{
-				CPUCount: int64(cpuTS[k][cpuCountIdx]),
-				CPUUsage: cpuTS[k][cpuUsageIdx],
-			},
-		})
-	}
+// Persist writes every series of result in a single appender commit.
+// Samples are stored with millisecond timestamps, as expected by the
+// Prometheus TSDB.
+func (s *Store) Persist(ctx context.Context, result *model.ProbesResult) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	slices.SortFunc(result, func(a, b model.ProbesResult) int {
-		return int(a.Timestamp.Unix() - b.Timestamp.Unix())
-	})
+	if s.db == nil {
+		return ErrStoreClosed
+	}
 
-	return result, nil
-}
+	timestamp := result.Timestamp.UnixMilli()
 
-func saveTemperature(ctx context.Context, db *tsdb.DB, result model.TemperatureResult, timestamp int64) error {
-	if err := persist(ctx, db, timestamp, result.Temperature, []string{dimensionLabelName, temperature, "unit", "celsius"}); err != nil {
-		return fmt.Errorf("appending temperature: %w", err)
+	appender := s.db.Appender(ctx)
+	for _, smp := range Samples(result) {
+		if _, err := appender.Append(0, smp.Labels, timestamp, smp.Value); err != nil {
+			_ = appender.Rollback()
+			return fmt.Errorf("appending %s: %w", smp.Name(), err)
+		}
 	}
-
-	if err := persist(ctx, db, timestamp, float64(result.RawTemperature), []string{dimensionLabelName, rawTemperature}); err != nil {
-		return fmt.Errorf("appending raw temperature: %w", err)
+	if err := appender.Commit(); err != nil {
+		return fmt.Errorf("committing samples: %w", err)
 	}
-
 	return nil
 }
 
-func getTemperature(ctx context.Context, db *tsdb.DB) (map[int64][2]float64, error) {
-	t, err := fetch(ctx, db, [2]string{dimensionLabelName, temperature})
-	if err != nil {
-		return nil, fmt.Errorf("fetching temperature: %w", err)
-	}
-	rt, err := fetch(ctx, db, [2]string{dimensionLabelName, rawTemperature})
-	if err != nil {
-		return nil, fmt.Errorf("fetching raw temperature: %w", err)
+// Filter narrows down the measurements returned by Store.Get.
+type Filter struct {
+	// From and To limit the time range, a zero value means unbounded.
+	From time.Time
+	To   time.Time
+	// Metrics limits the series fetched, all of them when empty.
+	Metrics []string
+	// Step averages samples into buckets of this size, aligned to From
+	// (or to the unix epoch when From is zero). Raw samples are returned
+	// when it's zero.
+	Step time.Duration
+}
+
+func (f Filter) timeRange() (int64, int64) {
+	mint, maxt := int64(math.MinInt64), int64(math.MaxInt64)
+	if !f.From.IsZero() {
+		mint = f.From.UnixMilli()
 	}
-	result := make(map[int64][2]float64)
-	for k, v := range t {
-		result[k] = [2]float64{
-			v,
-			rt[k],
-		}
+	if !f.To.IsZero() {
+		maxt = f.To.UnixMilli()
 	}
-
-	return result, nil
+	return mint, maxt
 }
 
-func saveMemoryUsage(ctx context.Context, db *tsdb.DB, result model.MemoryResult, timestamp int64) error {
-	if err := persist(ctx, db, timestamp, result.MemoryUsagePercentage, []string{dimensionLabelName, memoryUsagePercentage, "unit", "percent"}); err != nil {
-		return fmt.Errorf("appending memory usage percentage: %w", err)
-	}
-	if err := persist(ctx, db, timestamp, float64(result.UsedMemory), []string{dimensionLabelName, usedMemory, "unit", "bytes"}); err != nil {
-		return fmt.Errorf("appending used memory: %w", err)
+func (f Filter) matcher() *labels.Matcher {
+	if len(f.Metrics) == 0 {
+		return labels.MustNewMatcher(labels.MatchRegexp, DimensionLabelName, ".+")
 	}
-	if err := persist(ctx, db, timestamp, float64(result.TotalMemory), []string{dimensionLabelName, totalMemory, "unit", "bytes"}); err != nil {
-		return fmt.Errorf("appending total memory: %w", err)
+	names := make([]string, 0, len(f.Metrics))
+	for _, m := range f.Metrics {
+		names = append(names, regexp.QuoteMeta(m))
 	}
-	return nil
+	return labels.MustNewMatcher(labels.MatchRegexp, DimensionLabelName, strings.Join(names, "|"))
 }
 
-func getMemoryUsage(ctx context.Context, db *tsdb.DB) (map[int64][3]float64, error) {
-	var result = make(map[int64][3]float64)
-	mup, err := fetch(ctx, db, [2]string{dimensionLabelName, memoryUsagePercentage})
-	if err != nil {
-		return nil, err
+// Get returns the measurements matching f, sorted by timestamp.
+func (s *Store) Get(ctx context.Context, f Filter) ([]model.ProbesResult, error) {
+	if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return nil, ErrInvalidRange
 	}
-	um, err := fetch(ctx, db, [2]string{dimensionLabelName, usedMemory})
-	if err != nil {
-		return nil, err
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, ErrStoreClosed
 	}
-	tm, err := fetch(ctx, db, [2]string{dimensionLabelName, totalMemory})
+
+	type seriesPoints struct {
+		lbls   labels.Labels
+		points []Point
+	}
+	bySeries := make(map[string]*seriesPoints)
+
+	mint, maxt := f.timeRange()
+	err := s.fetch(ctx, mint, maxt, func(lbls labels.Labels, ts int64, v float64) {
+		sp, ok := bySeries[lbls.String()]
+		if !ok {
+			sp = &seriesPoints{lbls: lbls}
+			bySeries[lbls.String()] = sp
+		}
+		sp.points = append(sp.points, Point{Timestamp: time.UnixMilli(ts), Value: v})
+	}, f.matcher(), notMatcher(eventsMatcher))
 	if err != nil {
 		return nil, err
 	}
 
-	for k, v := range mup {
-		result[k] = [3]float64{
-			v,
-			um[k],
-			tm[k],
+	byTimestamp := make(map[int64]*model.ProbesResult)
+	for _, sp := range bySeries {
+		points := sp.points
+		if f.Step > 0 {
+			slices.SortFunc(points, func(a, b Point) int {
+				return a.Timestamp.Compare(b.Timestamp)
+			})
+			start := f.From
+			if start.IsZero() {
+				start = time.UnixMilli(0)
+			}
+			points = Downsample(points, start, f.Step)
+		}
+		for _, p := range points {
+			ts := p.Timestamp.UnixMilli()
+			r, ok := byTimestamp[ts]
+			if !ok {
+				r = &model.ProbesResult{Timestamp: time.UnixMilli(ts)}
+				byTimestamp[ts] = r
+			}
+			apply(r, sp.lbls, p.Value)
 		}
 	}
-	return result, nil
-}
 
-func saveCPUUsage(ctx context.Context, db *tsdb.DB, result model.CPUResult, timestamp int64) error {
-	if err := persist(ctx, db, timestamp, result.CPUUsage, []string{dimensionLabelName, cpuUsage, "unit", "percent"}); err != nil {
-		return fmt.Errorf("appending cpu usage: %w", err)
-	}
-	if err := persist(ctx, db, timestamp, float64(result.CPUCount), []string{dimensionLabelName, cpuCount, "unit", "count"}); err != nil {
-		return fmt.Errorf("appending cpu count: %w", err)
+	result := make([]model.ProbesResult, 0, len(byTimestamp))
+	for _, r := range byTimestamp {
+		normalize(r)
+		result = append(result, *r)
 	}
 
-	return nil
+	slices.SortFunc(result, func(a, b model.ProbesResult) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return result, nil
 }
 
-func getCPUUsage(ctx context.Context, db *tsdb.DB) (map[int64][2]float64, error) {
-	cc, err := fetch(ctx, db, [2]string{dimensionLabelName, cpuCount})
-	if err != nil {
-		return nil, fmt.Errorf("fetching cpu usage: %w", err)
-	}
-	cu, err := fetch(ctx, db, [2]string{dimensionLabelName, cpuUsage})
-	if err != nil {
-		return nil, fmt.Errorf("fetching cpu usage: %w", err)
-	}
+// Prune deletes every sample older than before and rewrites the
+// affected blocks to reclaim disk space.
+func (s *Store) Prune(ctx context.Context, before time.Time) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	result := make(map[int64][2]float64)
-	for k, v := range cc {
-		result[k] = [2]float64{
-			v,
-			cu[k],
-		}
+	if s.db == nil {
+		return ErrStoreClosed
 	}
-	return result, nil
-}
 
-func persist(ctx context.Context, db *tsdb.DB, timestamp int64, value float64, lbl []string) error {
-	appender := db.Appender(ctx)
-	defer func() {
-		_ = appender.Rollback()
-	}()
-	_, err := appender.Append(0, labels.FromStrings(lbl...), timestamp, value)
-	if err != nil {
-		return fmt.Errorf("appending temperature: %w", err)
+	if err := s.db.Delete(ctx, math.MinInt64, before.UnixMilli()-1, labels.MustNewMatcher(labels.MatchRegexp, DimensionLabelName, ".+")); err != nil {
+		return fmt.Errorf("deleting samples: %w", err)
+	}
+	if err := s.db.CleanTombstones(); err != nil {
+		return fmt.Errorf("cleaning tombstones: %w", err)
 	}
-	return appender.Commit()
+	return nil
 }
 
-func fetch(ctx context.Context, db *tsdb.DB, lbl [2]string) (map[int64]float64, error) {
-	querier, err := db.Querier(math.MinInt64, math.MaxInt64)
+// fetch calls fn for every float sample of the series matching ms in
+// the [mint, maxt] range.
+func (s *Store) fetch(ctx context.Context, mint, maxt int64, fn func(lbls labels.Labels, ts int64, v float64), ms ...*labels.Matcher) error {
+	querier, err := s.db.Querier(mint, maxt)
 	if err != nil {
-		return nil, fmt.Errorf("opening querier: %w", err)
+		return fmt.Errorf("opening querier: %w", err)
 	}
 	defer func() {
 		_ = querier.Close()
 	}()
-	queryResult := querier.Select(ctx, false, nil, labels.MustNewMatcher(labels.MatchEqual, lbl[0], lbl[1]))
+	queryResult := querier.Select(ctx, false, nil, ms...)
 
-	var result = make(map[int64]float64)
+	var it chunkenc.Iterator
 	for queryResult.Next() {
 		series := queryResult.At()
+		lbls := series.Labels()
 
-		it := series.Iterator(nil)
+		it = series.Iterator(it)
 		for it.Next() == chunkenc.ValFloat {
-			ts, v := it.At() // We ignore the timestamp here, only to have a predictable output we can test against (below)
-			result[ts] = v
+			ts, v := it.At()
+			fn(lbls, ts, v)
 		}
+		if err := it.Err(); err != nil {
+			return fmt.Errorf("iterating %s: %w", lbls.String(), err)
+		}
+	}
+	if err := queryResult.Err(); err != nil {
+		return fmt.Errorf("querying series: %w", err)
 	}
 
-	return result, nil
+	return nil
+}
+
+var defaultStore struct {
+	sync.Mutex
+	s *Store
+}
+
+// Default returns the process wide Store, opening it on the first call.
+func Default() (*Store, error) {
+	defaultStore.Lock()
+	defer defaultStore.Unlock()
+
+	if defaultStore.s != nil {
+		return defaultStore.s, nil
+	}
+	if _, err := os.Stat(legacyDBPath); err == nil {
+		slog.With("legacy_path", legacyDBPath, "path", Path()).
+			Warn("found a database in the legacy location, migrate it with 'db migrate-timestamps --path' and move it to the new path to keep its data")
+	}
+	s, err := Open(
+		Path(),
+		WithRetention(config.GetStorageRetention()),
+		WithMaxBytes(config.GetStorageMaxSize()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defaultStore.s = s
+	return s, nil
+}
+
+// Close closes the process wide Store if it was opened.
+func Close() error {
+	defaultStore.Lock()
+	defer defaultStore.Unlock()
+
+	if defaultStore.s == nil {
+		return nil
+	}
+	err := defaultStore.s.Close()
+	defaultStore.s = nil
+	return err
 }
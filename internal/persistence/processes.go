@@ -0,0 +1,332 @@
+package persistence
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+const (
+	processesDir = "processes"
+
+	// process snapshots are kept in a JSON lines file per UTC day,
+	// compressed once the day is over
+	processesFileLayout = "2006-01-02"
+	processesFileExt    = ".jsonl"
+	processesGzipExt    = ".jsonl.gz"
+
+	day = 24 * time.Hour
+)
+
+var (
+	ErrNoProcessesSnapshot = errors.New("no processes snapshot found")
+)
+
+// ProcessesStore keeps the processes snapshots beside the TSDB, as
+// they're lists of records rather than series.
+type ProcessesStore struct {
+	mu        sync.Mutex
+	dir       string
+	retention time.Duration
+	// lastDay is the day of the last written snapshot, older days are
+	// compacted and pruned when it changes
+	lastDay string
+}
+
+// ProcessesPath returns the location of the process wide processes
+// store, inside the configured storage directory.
+func ProcessesPath() string {
+	return filepath.Join(config.GetStoragePath(), processesDir)
+}
+
+// OpenProcesses opens (or creates) the processes store at dir. Days
+// older than retention are removed as new snapshots are written, zero
+// keeps them forever.
+func OpenProcesses(dir string, retention time.Duration) (*ProcessesStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating processes store: %w", err)
+	}
+	return &ProcessesStore{dir: dir, retention: retention}, nil
+}
+
+// Persist appends snapshot to the file of its day.
+func (s *ProcessesStore) Persist(ctx context.Context, snapshot *model.ProcessesSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("encoding processes snapshot: %w", err)
+	}
+
+	d := snapshot.Timestamp.UTC().Format(processesFileLayout)
+	f, err := os.OpenFile(filepath.Join(s.dir, d+processesFileExt), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening processes file: %w", err)
+	}
+	_, err = f.Write(append(b, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing processes snapshot: %w", err)
+	}
+
+	if d != s.lastDay {
+		s.lastDay = d
+		if err := s.compact(d); err != nil {
+			slog.With("error", err, "path", s.dir).WarnContext(ctx, "failed to compact processes store")
+		}
+		if s.retention > 0 {
+			if err := s.prune(snapshot.Timestamp.Add(-s.retention)); err != nil {
+				slog.With("error", err, "path", s.dir).WarnContext(ctx, "failed to prune processes store")
+			}
+		}
+	}
+	return nil
+}
+
+// At returns the snapshot taken closest to at.
+func (s *ProcessesStore) At(ctx context.Context, at time.Time) (*model.ProcessesSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var closest *model.ProcessesSnapshot
+	// the closest snapshot may be in the file of a neighbouring day
+	for _, t := range []time.Time{at.Add(-day), at, at.Add(day)} {
+		err := s.read(t.UTC().Format(processesFileLayout), func(snapshot *model.ProcessesSnapshot) {
+			if closest == nil || snapshot.Timestamp.Sub(at).Abs() < closest.Timestamp.Sub(at).Abs() {
+				closest = snapshot
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if closest == nil {
+		return nil, ErrNoProcessesSnapshot
+	}
+	return closest, nil
+}
+
+// Prune deletes every snapshot taken before before. The snapshots of
+// the current UTC day are kept until the day is over, as its file may
+// be appended by the daemon while another process prunes the store.
+func (s *ProcessesStore) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.prune(before)
+}
+
+func (s *ProcessesStore) prune(before time.Time) error {
+	days, err := s.days()
+	if err != nil {
+		return err
+	}
+	today := time.Now().UTC().Format(processesFileLayout)
+	cutoff := before.UTC().Format(processesFileLayout)
+	for _, d := range days {
+		if d >= today {
+			break
+		}
+		if d < cutoff {
+			if err := s.remove(d); err != nil {
+				return err
+			}
+			continue
+		}
+		if d == cutoff {
+			return s.rewrite(d, func(snapshot *model.ProcessesSnapshot) bool {
+				return !snapshot.Timestamp.Before(before)
+			})
+		}
+	}
+	return nil
+}
+
+// compact compresses the files of the days before current.
+func (s *ProcessesStore) compact(current string) error {
+	days, err := s.days()
+	if err != nil {
+		return err
+	}
+	for _, d := range days {
+		if d >= current {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(s.dir, d+processesFileExt)); err != nil {
+			// already compressed
+			continue
+		}
+		if err := s.rewrite(d, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewrite writes the snapshots of day d accepted by keep, all of them
+// when it's nil, into its compressed file.
+func (s *ProcessesStore) rewrite(d string, keep func(*model.ProcessesSnapshot) bool) error {
+	var kept []*model.ProcessesSnapshot
+	err := s.read(d, func(snapshot *model.ProcessesSnapshot) {
+		if keep == nil || keep(snapshot) {
+			kept = append(kept, snapshot)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, d+processesGzipExt)
+	tmpPath := path + ".tmp"
+	if err := writeGzipLines(tmpPath, kept); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replacing processes file: %w", err)
+	}
+	if err := os.Remove(filepath.Join(s.dir, d+processesFileExt)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing processes file: %w", err)
+	}
+	return nil
+}
+
+func writeGzipLines(path string, snapshots []*model.ProcessesSnapshot) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating processes file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	zw := gzip.NewWriter(f)
+	enc := json.NewEncoder(zw)
+	for _, snapshot := range snapshots {
+		if err := enc.Encode(snapshot); err != nil {
+			return fmt.Errorf("encoding processes snapshot: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("compressing processes file: %w", err)
+	}
+	return f.Close()
+}
+
+func (s *ProcessesStore) remove(d string) error {
+	for _, ext := range []string{processesFileExt, processesGzipExt} {
+		if err := os.Remove(filepath.Join(s.dir, d+ext)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("removing processes file: %w", err)
+		}
+	}
+	return nil
+}
+
+// days lists the days with snapshots, oldest first.
+func (s *ProcessesStore) days() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("listing processes files: %w", err)
+	}
+	var days []string
+	for _, e := range entries {
+		name := e.Name()
+		d, ok := strings.CutSuffix(name, processesGzipExt)
+		if !ok {
+			d, ok = strings.CutSuffix(name, processesFileExt)
+		}
+		if _, err := time.Parse(processesFileLayout, d); !ok || err != nil {
+			continue
+		}
+		if !slices.Contains(days, d) {
+			days = append(days, d)
+		}
+	}
+	slices.Sort(days)
+	return days, nil
+}
+
+// read calls fn for every snapshot of day d, reading both its
+// compressed and plain files.
+func (s *ProcessesStore) read(d string, fn func(*model.ProcessesSnapshot)) error {
+	for _, ext := range []string{processesGzipExt, processesFileExt} {
+		path := filepath.Join(s.dir, d+ext)
+		f, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("opening processes file: %w", err)
+		}
+		err = readLines(f, ext == processesGzipExt, fn)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func readLines(f *os.File, compressed bool, fn func(*model.ProcessesSnapshot)) error {
+	var r io.Reader = f
+	if compressed {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = zr.Close() }()
+		r = zr
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var snapshot model.ProcessesSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			// skip lines truncated by a crash while writing
+			continue
+		}
+		fn(&snapshot)
+	}
+	return scanner.Err()
+}
+
+var defaultProcesses struct {
+	sync.Mutex
+	s *ProcessesStore
+}
+
+// DefaultProcesses returns the process wide ProcessesStore, opening it
+// on the first call.
+func DefaultProcesses() (*ProcessesStore, error) {
+	defaultProcesses.Lock()
+	defer defaultProcesses.Unlock()
+
+	if defaultProcesses.s != nil {
+		return defaultProcesses.s, nil
+	}
+	s, err := OpenProcesses(ProcessesPath(), config.GetStorageRetention())
+	if err != nil {
+		return nil, err
+	}
+	defaultProcesses.s = s
+	return s, nil
+}
@@ -0,0 +1,150 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+func persistSnapshots(t *testing.T, s *ProcessesStore, timestamps ...time.Time) {
+	t.Helper()
+	for _, ts := range timestamps {
+		if err := s.Persist(context.Background(), &model.ProcessesSnapshot{Timestamp: ts}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func processesFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func snapshotTimes(t *testing.T, s *ProcessesStore, d time.Time) []time.Time {
+	t.Helper()
+	var times []time.Time
+	err := s.read(d.Format(processesFileLayout), func(snapshot *model.ProcessesSnapshot) {
+		times = append(times, snapshot.Timestamp)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return times
+}
+
+func TestProcessesRotation(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenProcesses(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	yesterday := time.Now().UTC().Truncate(day).Add(-day)
+	today := yesterday.Add(day)
+	persistSnapshots(t, s, yesterday.Add(time.Hour), yesterday.Add(2*time.Hour))
+
+	name := func(d time.Time, ext string) string { return d.Format(processesFileLayout) + ext }
+	if got, want := processesFiles(t, dir), []string{name(yesterday, processesFileExt)}; !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+
+	// the previous day is compressed once a snapshot of a new day is
+	// written
+	persistSnapshots(t, s, today.Add(time.Minute))
+	if got, want := processesFiles(t, dir), []string{name(yesterday, processesGzipExt), name(today, processesFileExt)}; !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := snapshotTimes(t, s, yesterday); len(got) != 2 {
+		t.Errorf("compressed snapshots = %v, want 2 of them", got)
+	}
+}
+
+func TestProcessesAt(t *testing.T) {
+	s, err := OpenProcesses(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.At(context.Background(), time.Now()); !errors.Is(err, ErrNoProcessesSnapshot) {
+		t.Fatalf("At() on an empty store error = %v, want %v", err, ErrNoProcessesSnapshot)
+	}
+
+	// two snapshots around each of two midnights
+	first := time.Now().UTC().Truncate(day).Add(-2 * day)
+	second := first.Add(day)
+	persistSnapshots(t, s,
+		first.Add(-3*time.Hour), first.Add(-5*time.Minute),
+		second.Add(-3*time.Hour), second.Add(3*time.Minute),
+	)
+
+	tests := []struct {
+		at   time.Time
+		want time.Time
+	}{
+		// the closest snapshot is in the file of the previous day
+		{at: first.Add(10 * time.Minute), want: first.Add(-5 * time.Minute)},
+		// or in the file of the next day
+		{at: second.Add(-10 * time.Minute), want: second.Add(3 * time.Minute)},
+		{at: second.Add(-2 * time.Hour), want: second.Add(-3 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := s.At(context.Background(), tt.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Timestamp.Equal(tt.want) {
+			t.Errorf("At(%v) = snapshot at %v, want %v", tt.at, got.Timestamp, tt.want)
+		}
+	}
+}
+
+func TestProcessesPrune(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenProcesses(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	today := time.Now().UTC().Truncate(day)
+	old, cutoffDay := today.Add(-3*day), today.Add(-2*day)
+	persistSnapshots(t, s,
+		old.Add(time.Hour),
+		cutoffDay.Add(time.Hour), cutoffDay.Add(3*time.Hour),
+		today.Add(time.Second),
+	)
+
+	if err := s.Prune(cutoffDay.Add(2 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if got := snapshotTimes(t, s, old); len(got) != 0 {
+		t.Errorf("snapshots of a pruned day = %v, want none", got)
+	}
+	if got, want := snapshotTimes(t, s, cutoffDay), []time.Time{cutoffDay.Add(3 * time.Hour)}; !slices.EqualFunc(got, want, time.Time.Equal) {
+		t.Errorf("snapshots of the cutoff day = %v, want %v", got, want)
+	}
+
+	// the file of the current day is left alone, it may be appended by
+	// the daemon while pruning
+	if err := s.Prune(time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if got := snapshotTimes(t, s, today); len(got) != 1 {
+		t.Errorf("snapshots of the current day = %v, want them kept", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, today.Format(processesFileLayout)+processesFileExt)); err != nil {
+		t.Errorf("current day file: %v", err)
+	}
+}
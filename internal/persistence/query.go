@@ -0,0 +1,213 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/util/annotations"
+)
+
+const (
+	queryTimeout    = 2 * time.Minute
+	queryMaxSamples = 5_000_000
+)
+
+var (
+	_ storage.Queryable = &namedQueryable{}
+	_ storage.Querier   = &namedQuerier{}
+)
+
+var engine = promql.NewEngine(promql.EngineOpts{
+	Logger:               &logger{l: slog.With("pkg", "persistence", "component", "promql")},
+	MaxSamples:           queryMaxSamples,
+	Timeout:              queryTimeout,
+	EnableAtModifier:     true,
+	EnableNegativeOffset: true,
+	NoStepSubqueryIntervalFn: func(int64) int64 {
+		return time.Minute.Milliseconds()
+	},
+})
+
+// QueryResult is the outcome of a PromQL query.
+type QueryResult struct {
+	ResultType parser.ValueType `json:"resultType"`
+	Result     parser.Value     `json:"result"`
+	Warnings   []string         `json:"warnings,omitempty"`
+}
+
+// Query evaluates the PromQL expression qs against the database. When
+// step is zero an instant query is evaluated at end, ignoring start,
+// otherwise a range query is evaluated from start to end.
+//
+// Series are stored with their name in the "dimension" label, which
+// is exposed to PromQL as the metric name instead, so vector matching
+// ignores it as usual (e.g. `used_memory / total_memory`).
+func (s *Store) Query(ctx context.Context, qs string, start, end time.Time, step time.Duration) (*QueryResult, error) {
+	if step > 0 && end.Before(start) {
+		return nil, ErrInvalidRange
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, ErrStoreClosed
+	}
+
+	queryable := &namedQueryable{q: s.db}
+
+	var q promql.Query
+	var err error
+	if step == 0 {
+		q, err = engine.NewInstantQuery(ctx, queryable, nil, qs, end)
+	} else {
+		q, err = engine.NewRangeQuery(ctx, queryable, nil, qs, start, end, step)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing query: %w", err)
+	}
+	defer q.Close()
+
+	res := q.Exec(ctx)
+	if res.Err != nil {
+		return nil, fmt.Errorf("executing query: %w", res.Err)
+	}
+
+	result := &QueryResult{
+		ResultType: res.Value.Type(),
+		Result:     res.Value,
+	}
+	for _, w := range res.Warnings.AsErrors() {
+		result.Warnings = append(result.Warnings, w.Error())
+	}
+	return result, nil
+}
+
+// namedQueryable maps the PromQL metric name to the dimension label
+// used by the database.
+type namedQueryable struct {
+	q storage.Queryable
+}
+
+func (n *namedQueryable) Querier(mint, maxt int64) (storage.Querier, error) {
+	q, err := n.q.Querier(mint, maxt)
+	if err != nil {
+		return nil, err
+	}
+	return &namedQuerier{q: q}, nil
+}
+
+type namedQuerier struct {
+	q storage.Querier
+}
+
+func (n *namedQuerier) Select(ctx context.Context, sortSeries bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	ss := n.q.Select(ctx, false, hints, renameMatchers(matchers)...)
+
+	var series []storage.Series
+	for ss.Next() {
+		s := ss.At()
+		series = append(series, &namedSeries{Series: s, lbls: withMetricName(s.Labels())})
+	}
+	if err := ss.Err(); err != nil {
+		return storage.ErrSeriesSet(err)
+	}
+	if sortSeries {
+		slices.SortFunc(series, func(a, b storage.Series) int {
+			return labels.Compare(a.Labels(), b.Labels())
+		})
+	}
+	return &listSeriesSet{series: series, idx: -1, warnings: ss.Warnings()}
+}
+
+func (n *namedQuerier) LabelValues(ctx context.Context, name string, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	switch name {
+	case DimensionLabelName:
+		// only exposed as the metric name
+		return nil, nil, nil
+	case labels.MetricName:
+		name = DimensionLabelName
+	}
+	return n.q.LabelValues(ctx, name, renameMatchers(matchers)...)
+}
+
+func (n *namedQuerier) LabelNames(ctx context.Context, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	names, warnings, err := n.q.LabelNames(ctx, renameMatchers(matchers)...)
+	if err != nil {
+		return nil, warnings, err
+	}
+	if i := slices.Index(names, DimensionLabelName); i >= 0 {
+		names[i] = labels.MetricName
+		slices.Sort(names)
+	}
+	return names, warnings, nil
+}
+
+func (n *namedQuerier) Close() error {
+	return n.q.Close()
+}
+
+// renameMatchers replaces matchers on the metric name by matchers on
+// the dimension label.
+func renameMatchers(matchers []*labels.Matcher) []*labels.Matcher {
+	result := make([]*labels.Matcher, 0, len(matchers))
+	for _, m := range matchers {
+		if m.Name == labels.MetricName {
+			m = labels.MustNewMatcher(m.Type, DimensionLabelName, m.Value)
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+// withMetricName moves the dimension label to the metric name.
+func withMetricName(lbls labels.Labels) labels.Labels {
+	b := labels.NewBuilder(lbls)
+	b.Set(labels.MetricName, lbls.Get(DimensionLabelName))
+	b.Del(DimensionLabelName)
+	return b.Labels()
+}
+
+type namedSeries struct {
+	storage.Series
+	lbls labels.Labels
+}
+
+func (n *namedSeries) Labels() labels.Labels {
+	return n.lbls
+}
+
+func (n *namedSeries) Iterator(it chunkenc.Iterator) chunkenc.Iterator {
+	return n.Series.Iterator(it)
+}
+
+type listSeriesSet struct {
+	series   []storage.Series
+	idx      int
+	warnings annotations.Annotations
+}
+
+func (l *listSeriesSet) Next() bool {
+	l.idx++
+	return l.idx < len(l.series)
+}
+
+func (l *listSeriesSet) At() storage.Series {
+	return l.series[l.idx]
+}
+
+func (l *listSeriesSet) Err() error {
+	return nil
+}
+
+func (l *listSeriesSet) Warnings() annotations.Annotations {
+	return l.warnings
+}
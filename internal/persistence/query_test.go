@@ -0,0 +1,49 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/prometheus/prometheus/promql"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(t.TempDir(), WithRetention(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestQueryRange(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	now := time.Now().Truncate(time.Second)
+	result := model.ProbesResult{Timestamp: now.Add(-2 * time.Hour)}
+	result.Temp.Temperature = 42.5
+	if err := store.Persist(ctx, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	// the start of an instant query means nothing, even when it's after
+	// the evaluation time
+	res, err := store.Query(ctx, "temperature", now.Add(-time.Hour), now.Add(-2*time.Hour), 0)
+	if err != nil {
+		t.Fatalf("instant query: %v", err)
+	}
+	vector, ok := res.Result.(promql.Vector)
+	if !ok || len(vector) != 1 || vector[0].F != 42.5 {
+		t.Errorf("instant query = %v, want temperature 42.5", res.Result)
+	}
+
+	_, err = store.Query(ctx, "temperature", now.Add(-time.Hour), now.Add(-2*time.Hour), time.Minute)
+	if !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("range query error = %v, want %v", err, ErrInvalidRange)
+	}
+}
@@ -0,0 +1,544 @@
+package persistence
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// Sample is the value of a single series as written to the database.
+type Sample struct {
+	Labels labels.Labels
+	Value  float64
+}
+
+// Name returns the metric name of the series.
+func (s Sample) Name() string {
+	return s.Labels.Get(DimensionLabelName)
+}
+
+func newSample(value float64, lbl ...string) Sample {
+	return Sample{
+		Labels: labels.FromStrings(lbl...),
+		Value:  value,
+	}
+}
+
+// Samples flattens result into the series written to the database.
+func Samples(result *model.ProbesResult) []Sample {
+	var s []Sample
+	s = append(s, temperatureSamples(result.Temp)...)
+	s = append(s, memorySamples(result.Memory)...)
+	s = append(s, cpuSamples(result.CPU)...)
+	s = append(s, loadSamples(result.Load)...)
+	s = append(s, uptimeSamples(result.Uptime)...)
+	s = append(s, pressureSamples(result.Pressure)...)
+	s = append(s, throttlingSamples(result.Throttling)...)
+	s = append(s, filesystemSamples(result.Filesystems)...)
+	s = append(s, diskIOSamples(result.DiskIO)...)
+	s = append(s, networkSamples(result.Network)...)
+	s = append(s, wirelessSamples(result.Wireless)...)
+	return s
+}
+
+// apply sets the field of result represented by the series lbls.
+func apply(result *model.ProbesResult, lbls labels.Labels, v float64) {
+	switch lbls.Get(DimensionLabelName) {
+	case temperature:
+		result.Temp.Temperature = v
+	case rawTemperature:
+		result.Temp.RawTemperature = int64(v)
+	case sensorTemperature:
+		sensor := lbls.Get(sensorLabelName)
+		entry(&result.Temp.Sensors, func(t model.TemperatureSensorResult) bool {
+			return t.Sensor == sensor
+		}, model.TemperatureSensorResult{
+			Sensor: sensor,
+			Chip:   lbls.Get(chipLabelName),
+			Label:  lbls.Get(labelLabelName),
+		}).Temperature = v
+	case memoryUsagePercentage:
+		result.Memory.MemoryUsagePercentage = v
+	case usedMemory:
+		result.Memory.UsedMemory = int64(v)
+	case totalMemory:
+		result.Memory.TotalMemory = int64(v)
+	case availableMemory:
+		result.Memory.AvailableMemory = int64(v)
+	case buffersMemory:
+		result.Memory.BuffersMemory = int64(v)
+	case cachedMemory:
+		result.Memory.CachedMemory = int64(v)
+	case sharedMemory:
+		result.Memory.SharedMemory = int64(v)
+	case dirtyMemory:
+		result.Memory.DirtyMemory = int64(v)
+	case writebackMemory:
+		result.Memory.WritebackMemory = int64(v)
+	case usedSwap:
+		result.Memory.UsedSwap = int64(v)
+	case totalSwap:
+		result.Memory.TotalSwap = int64(v)
+	case swapInRate:
+		result.Memory.SwapInBytesPerSecond = v
+	case swapOutRate:
+		result.Memory.SwapOutBytesPerSecond = v
+	case zramDiskSize:
+		zramEntry(result, lbls).DiskSize = int64(v)
+	case zramOriginalDataSize:
+		zramEntry(result, lbls).OriginalDataSize = int64(v)
+	case zramCompressedDataSize:
+		zramEntry(result, lbls).CompressedDataSize = int64(v)
+	case zramMemoryUsed:
+		zramEntry(result, lbls).MemoryUsed = int64(v)
+	case load1:
+		result.Load.Load1 = v
+	case load5:
+		result.Load.Load5 = v
+	case load15:
+		result.Load.Load15 = v
+	case procsRunning:
+		result.Load.ProcsRunning = int64(v)
+	case procsBlocked:
+		result.Load.ProcsBlocked = int64(v)
+	case uptime:
+		result.Uptime.UptimeSeconds = int64(v)
+	case bootTime:
+		result.Uptime.BootTime = time.Unix(int64(v), 0)
+	case bootInfo:
+		result.Uptime.BootID = lbls.Get(bootIDLabelName)
+	case throttlingNow:
+		throttlingEntry(result).Now.Set(lbls.Get(conditionLabelName), v != 0)
+	case throttlingOccurred:
+		throttlingEntry(result).Occurred.Set(lbls.Get(conditionLabelName), v != 0)
+	case voltage:
+		rail := lbls.Get(railLabelName)
+		t := throttlingEntry(result)
+		entry(&t.Voltages, func(r model.VoltageResult) bool {
+			return r.Rail == rail
+		}, model.VoltageResult{Rail: rail}).Volts = v
+	case filesystemTotal:
+		filesystemEntry(result, lbls).Total = int64(v)
+	case filesystemUsed:
+		filesystemEntry(result, lbls).Used = int64(v)
+	case filesystemFree:
+		filesystemEntry(result, lbls).Free = int64(v)
+	case filesystemUsagePercentage:
+		filesystemEntry(result, lbls).UsagePercentage = v
+	case filesystemInodesTotal:
+		filesystemEntry(result, lbls).InodesTotal = int64(v)
+	case filesystemInodesUsed:
+		filesystemEntry(result, lbls).InodesUsed = int64(v)
+	case filesystemInodesFree:
+		filesystemEntry(result, lbls).InodesFree = int64(v)
+	case filesystemInodesUsagePercentage:
+		filesystemEntry(result, lbls).InodesUsagePercentage = v
+	case diskReadBytes:
+		diskIOEntry(result, lbls).ReadBytesPerSecond = v
+	case diskWriteBytes:
+		diskIOEntry(result, lbls).WriteBytesPerSecond = v
+	case diskReadIOPS:
+		diskIOEntry(result, lbls).ReadIOPS = v
+	case diskWriteIOPS:
+		diskIOEntry(result, lbls).WriteIOPS = v
+	case diskAwait:
+		diskIOEntry(result, lbls).AwaitMilliseconds = v
+	case diskUtilization:
+		diskIOEntry(result, lbls).UtilizationPercentage = v
+	case networkUp:
+		networkEntry(result, lbls).Up = v != 0
+	case networkSpeed:
+		networkEntry(result, lbls).SpeedMbps = int64(v)
+	case networkRxBytes:
+		networkEntry(result, lbls).RxBytesPerSecond = v
+	case networkTxBytes:
+		networkEntry(result, lbls).TxBytesPerSecond = v
+	case networkRxPackets:
+		networkEntry(result, lbls).RxPacketsPerSecond = v
+	case networkTxPackets:
+		networkEntry(result, lbls).TxPacketsPerSecond = v
+	case networkRxErrors:
+		networkEntry(result, lbls).RxErrorsPerSecond = v
+	case networkTxErrors:
+		networkEntry(result, lbls).TxErrorsPerSecond = v
+	case networkRxDrops:
+		networkEntry(result, lbls).RxDropsPerSecond = v
+	case networkTxDrops:
+		networkEntry(result, lbls).TxDropsPerSecond = v
+	case wirelessInfo:
+		wirelessEntry(result, lbls).SSID = lbls.Get(ssidLabelName)
+	case wirelessQuality:
+		wirelessEntry(result, lbls).LinkQuality = v
+	case wirelessSignal:
+		wirelessEntry(result, lbls).SignalDBm = v
+	case wirelessNoise:
+		wirelessEntry(result, lbls).NoiseDBm = v
+	case wirelessRxBitrate:
+		wirelessEntry(result, lbls).RxBitrateMbps = v
+	case wirelessTxBitrate:
+		wirelessEntry(result, lbls).TxBitrateMbps = v
+	case pressureAvg10:
+		pressureEntry(result, lbls).Avg10 = v
+	case pressureAvg60:
+		pressureEntry(result, lbls).Avg60 = v
+	case pressureAvg300:
+		pressureEntry(result, lbls).Avg300 = v
+	case pressureTotal:
+		pressureEntry(result, lbls).TotalMicroseconds = int64(v)
+	case cpuUsage:
+		result.CPU.CPUUsage = v
+	case cpuCount:
+		result.CPU.CPUCount = int64(v)
+	case cpuCoreUsage:
+		core, _ := strconv.Atoi(lbls.Get(coreLabelName))
+		entry(&result.CPU.Cores, func(c model.CPUCoreResult) bool {
+			return c.Core == core
+		}, model.CPUCoreResult{Core: core}).Usage = v
+	case cpuTime:
+		mode := lbls.Get(modeLabelName)
+		entry(&result.CPU.Times, func(t model.CPUTimeResult) bool {
+			return t.Mode == mode
+		}, model.CPUTimeResult{Mode: mode}).Percentage = v
+	case cpuFrequency:
+		cpuFrequencyEntry(result, lbls).Current = int64(v)
+	case cpuFrequencyMin:
+		cpuFrequencyEntry(result, lbls).Min = int64(v)
+	case cpuFrequencyMax:
+		cpuFrequencyEntry(result, lbls).Max = int64(v)
+	case cpuGovernor:
+		cpuFrequencyEntry(result, lbls).Governor = lbls.Get(governorLabelName)
+	}
+}
+
+// normalize sorts the labelled entries of result, which apply fills in
+// the order the series are read.
+func normalize(result *model.ProbesResult) {
+	slices.SortFunc(result.CPU.Cores, func(a, b model.CPUCoreResult) int {
+		return a.Core - b.Core
+	})
+	slices.SortFunc(result.CPU.Frequencies, func(a, b model.CPUFrequencyResult) int {
+		return a.Core - b.Core
+	})
+	slices.SortFunc(result.Temp.Sensors, func(a, b model.TemperatureSensorResult) int {
+		return strings.Compare(a.Sensor, b.Sensor)
+	})
+	slices.SortFunc(result.Filesystems, func(a, b model.FilesystemResult) int {
+		return strings.Compare(a.Mountpoint, b.Mountpoint)
+	})
+	slices.SortFunc(result.Memory.Zram, func(a, b model.ZramResult) int {
+		return strings.Compare(a.Device, b.Device)
+	})
+	slices.SortFunc(result.Pressure, func(a, b model.PressureResult) int {
+		return cmp.Or(
+			slices.Index(model.PressureResources, a.Resource)-slices.Index(model.PressureResources, b.Resource),
+			strings.Compare(a.Kind, b.Kind),
+		)
+	})
+	slices.SortFunc(result.DiskIO, func(a, b model.DiskIOResult) int {
+		return strings.Compare(a.Device, b.Device)
+	})
+	slices.SortFunc(result.Network, func(a, b model.NetworkResult) int {
+		return strings.Compare(a.Interface, b.Interface)
+	})
+	slices.SortFunc(result.Wireless, func(a, b model.WirelessResult) int {
+		return strings.Compare(a.Interface, b.Interface)
+	})
+	if result.Throttling != nil {
+		slices.SortFunc(result.Throttling.Voltages, func(a, b model.VoltageResult) int {
+			return strings.Compare(a.Rail, b.Rail)
+		})
+	}
+	slices.SortFunc(result.CPU.Times, func(a, b model.CPUTimeResult) int {
+		return slices.Index(model.CPUTimeModes, a.Mode) - slices.Index(model.CPUTimeModes, b.Mode)
+	})
+}
+
+// entry returns the element of s matching match, appending newEntry to
+// s when there's none.
+func entry[T any](s *[]T, match func(T) bool, newEntry T) *T {
+	if i := slices.IndexFunc(*s, match); i >= 0 {
+		return &(*s)[i]
+	}
+	*s = append(*s, newEntry)
+	return &(*s)[len(*s)-1]
+}
+
+func cpuFrequencyEntry(result *model.ProbesResult, lbls labels.Labels) *model.CPUFrequencyResult {
+	core, _ := strconv.Atoi(lbls.Get(coreLabelName))
+	return entry(&result.CPU.Frequencies, func(f model.CPUFrequencyResult) bool {
+		return f.Core == core
+	}, model.CPUFrequencyResult{Core: core})
+}
+
+func filesystemEntry(result *model.ProbesResult, lbls labels.Labels) *model.FilesystemResult {
+	mountpoint := lbls.Get(mountpointLabelName)
+	return entry(&result.Filesystems, func(f model.FilesystemResult) bool {
+		return f.Mountpoint == mountpoint
+	}, model.FilesystemResult{
+		Mountpoint: mountpoint,
+		Device:     lbls.Get(deviceLabelName),
+		FSType:     lbls.Get(fstypeLabelName),
+	})
+}
+
+func zramEntry(result *model.ProbesResult, lbls labels.Labels) *model.ZramResult {
+	device := lbls.Get(deviceLabelName)
+	return entry(&result.Memory.Zram, func(z model.ZramResult) bool {
+		return z.Device == device
+	}, model.ZramResult{Device: device})
+}
+
+func pressureEntry(result *model.ProbesResult, lbls labels.Labels) *model.PressureResult {
+	resource, kind := lbls.Get(resourceLabelName), lbls.Get(kindLabelName)
+	return entry(&result.Pressure, func(p model.PressureResult) bool {
+		return p.Resource == resource && p.Kind == kind
+	}, model.PressureResult{Resource: resource, Kind: kind})
+}
+
+func diskIOEntry(result *model.ProbesResult, lbls labels.Labels) *model.DiskIOResult {
+	device := lbls.Get(deviceLabelName)
+	return entry(&result.DiskIO, func(d model.DiskIOResult) bool {
+		return d.Device == device
+	}, model.DiskIOResult{Device: device})
+}
+
+func networkEntry(result *model.ProbesResult, lbls labels.Labels) *model.NetworkResult {
+	name := lbls.Get(interfaceLabelName)
+	return entry(&result.Network, func(n model.NetworkResult) bool {
+		return n.Interface == name
+	}, model.NetworkResult{Interface: name})
+}
+
+func wirelessEntry(result *model.ProbesResult, lbls labels.Labels) *model.WirelessResult {
+	name := lbls.Get(interfaceLabelName)
+	return entry(&result.Wireless, func(w model.WirelessResult) bool {
+		return w.Interface == name
+	}, model.WirelessResult{Interface: name})
+}
+
+// throttlingEntry returns the throttling state of result, which is
+// only set when the series are found.
+func throttlingEntry(result *model.ProbesResult) *model.ThrottlingResult {
+	if result.Throttling == nil {
+		result.Throttling = &model.ThrottlingResult{}
+	}
+	return result.Throttling
+}
+
+func temperatureSamples(result model.TemperatureResult) []Sample {
+	s := []Sample{
+		newSample(result.Temperature, DimensionLabelName, temperature, unitLabelName, unitCelsius),
+		newSample(float64(result.RawTemperature), DimensionLabelName, rawTemperature),
+	}
+	for _, t := range result.Sensors {
+		lbl := []string{DimensionLabelName, sensorTemperature, sensorLabelName, t.Sensor, unitLabelName, unitCelsius}
+		if t.Chip != "" {
+			lbl = append(lbl, chipLabelName, t.Chip)
+		}
+		if t.Label != "" {
+			lbl = append(lbl, labelLabelName, t.Label)
+		}
+		s = append(s, newSample(t.Temperature, lbl...))
+	}
+	return s
+}
+
+func memorySamples(result model.MemoryResult) []Sample {
+	s := []Sample{
+		newSample(result.MemoryUsagePercentage, DimensionLabelName, memoryUsagePercentage, unitLabelName, unitPercent),
+		newSample(float64(result.UsedMemory), DimensionLabelName, usedMemory, unitLabelName, unitBytes),
+		newSample(float64(result.TotalMemory), DimensionLabelName, totalMemory, unitLabelName, unitBytes),
+		newSample(float64(result.AvailableMemory), DimensionLabelName, availableMemory, unitLabelName, unitBytes),
+		newSample(float64(result.BuffersMemory), DimensionLabelName, buffersMemory, unitLabelName, unitBytes),
+		newSample(float64(result.CachedMemory), DimensionLabelName, cachedMemory, unitLabelName, unitBytes),
+		newSample(float64(result.SharedMemory), DimensionLabelName, sharedMemory, unitLabelName, unitBytes),
+		newSample(float64(result.DirtyMemory), DimensionLabelName, dirtyMemory, unitLabelName, unitBytes),
+		newSample(float64(result.WritebackMemory), DimensionLabelName, writebackMemory, unitLabelName, unitBytes),
+		newSample(float64(result.UsedSwap), DimensionLabelName, usedSwap, unitLabelName, unitBytes),
+		newSample(float64(result.TotalSwap), DimensionLabelName, totalSwap, unitLabelName, unitBytes),
+		newSample(result.SwapInBytesPerSecond, DimensionLabelName, swapInRate, unitLabelName, unitBytesPerSecond),
+		newSample(result.SwapOutBytesPerSecond, DimensionLabelName, swapOutRate, unitLabelName, unitBytesPerSecond),
+	}
+	for _, z := range result.Zram {
+		s = append(s,
+			newSample(float64(z.DiskSize), DimensionLabelName, zramDiskSize, deviceLabelName, z.Device, unitLabelName, unitBytes),
+			newSample(float64(z.OriginalDataSize), DimensionLabelName, zramOriginalDataSize, deviceLabelName, z.Device, unitLabelName, unitBytes),
+			newSample(float64(z.CompressedDataSize), DimensionLabelName, zramCompressedDataSize, deviceLabelName, z.Device, unitLabelName, unitBytes),
+			newSample(float64(z.MemoryUsed), DimensionLabelName, zramMemoryUsed, deviceLabelName, z.Device, unitLabelName, unitBytes),
+		)
+	}
+	return s
+}
+
+func cpuSamples(result model.CPUResult) []Sample {
+	s := []Sample{
+		newSample(result.CPUUsage, DimensionLabelName, cpuUsage, unitLabelName, unitPercent),
+		newSample(float64(result.CPUCount), DimensionLabelName, cpuCount, unitLabelName, unitCount),
+	}
+	for _, c := range result.Cores {
+		s = append(s, newSample(c.Usage, DimensionLabelName, cpuCoreUsage, coreLabelName, strconv.Itoa(c.Core), unitLabelName, unitPercent))
+	}
+	for _, t := range result.Times {
+		s = append(s, newSample(t.Percentage, DimensionLabelName, cpuTime, modeLabelName, t.Mode, unitLabelName, unitPercent))
+	}
+	for _, f := range result.Frequencies {
+		core := strconv.Itoa(f.Core)
+		s = append(s,
+			newSample(float64(f.Current), DimensionLabelName, cpuFrequency, coreLabelName, core, unitLabelName, unitHertz),
+			newSample(float64(f.Min), DimensionLabelName, cpuFrequencyMin, coreLabelName, core, unitLabelName, unitHertz),
+			newSample(float64(f.Max), DimensionLabelName, cpuFrequencyMax, coreLabelName, core, unitLabelName, unitHertz),
+		)
+		if f.Governor != "" {
+			// the governor is kept as a label of an info like series
+			s = append(s, newSample(1, DimensionLabelName, cpuGovernor, coreLabelName, core, governorLabelName, f.Governor))
+		}
+	}
+	return s
+}
+
+func loadSamples(result model.LoadResult) []Sample {
+	return []Sample{
+		newSample(result.Load1, DimensionLabelName, load1),
+		newSample(result.Load5, DimensionLabelName, load5),
+		newSample(result.Load15, DimensionLabelName, load15),
+		newSample(float64(result.ProcsRunning), DimensionLabelName, procsRunning, unitLabelName, unitCount),
+		newSample(float64(result.ProcsBlocked), DimensionLabelName, procsBlocked, unitLabelName, unitCount),
+	}
+}
+
+// uptimeSamples leaves out the fields that weren't measured, so a
+// failed probe, or a sample fetched without the other series, isn't
+// taken as a boot at the unix epoch.
+func uptimeSamples(result model.UptimeResult) []Sample {
+	var s []Sample
+	if result.UptimeSeconds > 0 {
+		s = append(s, newSample(float64(result.UptimeSeconds), DimensionLabelName, uptime, unitLabelName, unitSeconds))
+	}
+	if !result.BootTime.IsZero() {
+		s = append(s, newSample(float64(result.BootTime.Unix()), DimensionLabelName, bootTime, unitLabelName, unitSeconds))
+	}
+	if result.BootID != "" {
+		// the boot ID is kept as a label of an info like series
+		s = append(s, newSample(1, DimensionLabelName, bootInfo, bootIDLabelName, result.BootID))
+	}
+	return s
+}
+
+func throttlingSamples(result *model.ThrottlingResult) []Sample {
+	if result == nil {
+		return nil
+	}
+	var s []Sample
+	for _, c := range model.ThrottlingConditions {
+		s = append(s,
+			newSample(boolValue(result.Now.Get(c)), DimensionLabelName, throttlingNow, conditionLabelName, c),
+			newSample(boolValue(result.Occurred.Get(c)), DimensionLabelName, throttlingOccurred, conditionLabelName, c),
+		)
+	}
+	for _, v := range result.Voltages {
+		s = append(s, newSample(v.Volts, DimensionLabelName, voltage, railLabelName, v.Rail, unitLabelName, unitVolts))
+	}
+	return s
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func filesystemSamples(result []model.FilesystemResult) []Sample {
+	var s []Sample
+	for _, f := range result {
+		lbl := func(name, unit string) []string {
+			return []string{DimensionLabelName, name, mountpointLabelName, f.Mountpoint, deviceLabelName, f.Device, fstypeLabelName, f.FSType, unitLabelName, unit}
+		}
+		s = append(s,
+			newSample(float64(f.Total), lbl(filesystemTotal, unitBytes)...),
+			newSample(float64(f.Used), lbl(filesystemUsed, unitBytes)...),
+			newSample(float64(f.Free), lbl(filesystemFree, unitBytes)...),
+			newSample(f.UsagePercentage, lbl(filesystemUsagePercentage, unitPercent)...),
+			newSample(float64(f.InodesTotal), lbl(filesystemInodesTotal, unitCount)...),
+			newSample(float64(f.InodesUsed), lbl(filesystemInodesUsed, unitCount)...),
+			newSample(float64(f.InodesFree), lbl(filesystemInodesFree, unitCount)...),
+			newSample(f.InodesUsagePercentage, lbl(filesystemInodesUsagePercentage, unitPercent)...),
+		)
+	}
+	return s
+}
+
+func diskIOSamples(result []model.DiskIOResult) []Sample {
+	var s []Sample
+	for _, d := range result {
+		s = append(s,
+			newSample(d.ReadBytesPerSecond, DimensionLabelName, diskReadBytes, deviceLabelName, d.Device, unitLabelName, unitBytesPerSecond),
+			newSample(d.WriteBytesPerSecond, DimensionLabelName, diskWriteBytes, deviceLabelName, d.Device, unitLabelName, unitBytesPerSecond),
+			newSample(d.ReadIOPS, DimensionLabelName, diskReadIOPS, deviceLabelName, d.Device, unitLabelName, unitIOPS),
+			newSample(d.WriteIOPS, DimensionLabelName, diskWriteIOPS, deviceLabelName, d.Device, unitLabelName, unitIOPS),
+			newSample(d.AwaitMilliseconds, DimensionLabelName, diskAwait, deviceLabelName, d.Device, unitLabelName, unitMilliseconds),
+			newSample(d.UtilizationPercentage, DimensionLabelName, diskUtilization, deviceLabelName, d.Device, unitLabelName, unitPercent),
+		)
+	}
+	return s
+}
+
+func networkSamples(result []model.NetworkResult) []Sample {
+	var s []Sample
+	for _, n := range result {
+		s = append(s,
+			newSample(boolValue(n.Up), DimensionLabelName, networkUp, interfaceLabelName, n.Interface),
+			newSample(float64(n.SpeedMbps), DimensionLabelName, networkSpeed, interfaceLabelName, n.Interface, unitLabelName, unitMbps),
+			newSample(n.RxBytesPerSecond, DimensionLabelName, networkRxBytes, interfaceLabelName, n.Interface, unitLabelName, unitBytesPerSecond),
+			newSample(n.TxBytesPerSecond, DimensionLabelName, networkTxBytes, interfaceLabelName, n.Interface, unitLabelName, unitBytesPerSecond),
+			newSample(n.RxPacketsPerSecond, DimensionLabelName, networkRxPackets, interfaceLabelName, n.Interface, unitLabelName, unitPerSecond),
+			newSample(n.TxPacketsPerSecond, DimensionLabelName, networkTxPackets, interfaceLabelName, n.Interface, unitLabelName, unitPerSecond),
+			newSample(n.RxErrorsPerSecond, DimensionLabelName, networkRxErrors, interfaceLabelName, n.Interface, unitLabelName, unitPerSecond),
+			newSample(n.TxErrorsPerSecond, DimensionLabelName, networkTxErrors, interfaceLabelName, n.Interface, unitLabelName, unitPerSecond),
+			newSample(n.RxDropsPerSecond, DimensionLabelName, networkRxDrops, interfaceLabelName, n.Interface, unitLabelName, unitPerSecond),
+			newSample(n.TxDropsPerSecond, DimensionLabelName, networkTxDrops, interfaceLabelName, n.Interface, unitLabelName, unitPerSecond),
+		)
+	}
+	return s
+}
+
+func wirelessSamples(result []model.WirelessResult) []Sample {
+	var s []Sample
+	for _, w := range result {
+		s = append(s,
+			newSample(w.LinkQuality, DimensionLabelName, wirelessQuality, interfaceLabelName, w.Interface),
+			newSample(w.SignalDBm, DimensionLabelName, wirelessSignal, interfaceLabelName, w.Interface, unitLabelName, unitDBm),
+		)
+		if w.SSID != "" {
+			s = append(s, newSample(1, DimensionLabelName, wirelessInfo, interfaceLabelName, w.Interface, ssidLabelName, w.SSID))
+		}
+		if w.NoiseDBm != 0 {
+			s = append(s, newSample(w.NoiseDBm, DimensionLabelName, wirelessNoise, interfaceLabelName, w.Interface, unitLabelName, unitDBm))
+		}
+		if w.RxBitrateMbps != 0 {
+			s = append(s, newSample(w.RxBitrateMbps, DimensionLabelName, wirelessRxBitrate, interfaceLabelName, w.Interface, unitLabelName, unitMbps))
+		}
+		if w.TxBitrateMbps != 0 {
+			s = append(s, newSample(w.TxBitrateMbps, DimensionLabelName, wirelessTxBitrate, interfaceLabelName, w.Interface, unitLabelName, unitMbps))
+		}
+	}
+	return s
+}
+
+func pressureSamples(result []model.PressureResult) []Sample {
+	var s []Sample
+	for _, p := range result {
+		s = append(s,
+			newSample(p.Avg10, DimensionLabelName, pressureAvg10, resourceLabelName, p.Resource, kindLabelName, p.Kind, unitLabelName, unitPercent),
+			newSample(p.Avg60, DimensionLabelName, pressureAvg60, resourceLabelName, p.Resource, kindLabelName, p.Kind, unitLabelName, unitPercent),
+			newSample(p.Avg300, DimensionLabelName, pressureAvg300, resourceLabelName, p.Resource, kindLabelName, p.Kind, unitLabelName, unitPercent),
+			newSample(float64(p.TotalMicroseconds), DimensionLabelName, pressureTotal, resourceLabelName, p.Resource, kindLabelName, p.Kind, unitLabelName, unitMicroseconds),
+		)
+	}
+	return s
+}
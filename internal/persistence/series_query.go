@@ -0,0 +1,118 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+var (
+	ErrMetricRequired = errors.New("metric name is required")
+	ErrInvalidRange   = errors.New("end of the range is before its start")
+)
+
+// Point is a single sample of a Series.
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// Series is the list of samples of a metric for a given label set.
+type Series struct {
+	Metric string            `json:"metric"`
+	Labels map[string]string `json:"labels"`
+	Points []Point           `json:"points"`
+}
+
+// Series returns the samples of metric in the [from, to] range. When
+// step is greater than zero the samples are averaged into buckets of
+// that size, aligned to from.
+func (s *Store) Series(ctx context.Context, metric string, from, to time.Time, step time.Duration) ([]Series, error) {
+	if metric == "" {
+		return nil, ErrMetricRequired
+	}
+	if to.Before(from) {
+		return nil, ErrInvalidRange
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return nil, ErrStoreClosed
+	}
+
+	bySeries := make(map[string]*Series)
+	var keys []string
+	err := s.fetch(ctx, from.UnixMilli(), to.UnixMilli(), func(lbls labels.Labels, ts int64, v float64) {
+		key := lbls.String()
+		series, ok := bySeries[key]
+		if !ok {
+			series = &Series{
+				Metric: metric,
+				Labels: labelsMap(lbls),
+			}
+			bySeries[key] = series
+			keys = append(keys, key)
+		}
+		series.Points = append(series.Points, Point{Timestamp: time.UnixMilli(ts), Value: v})
+	}, labels.MustNewMatcher(labels.MatchEqual, DimensionLabelName, metric))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", metric, err)
+	}
+
+	slices.Sort(keys)
+	result := make([]Series, 0, len(keys))
+	for _, k := range keys {
+		series := bySeries[k]
+		slices.SortFunc(series.Points, func(a, b Point) int {
+			return a.Timestamp.Compare(b.Timestamp)
+		})
+		if step > 0 {
+			series.Points = Downsample(series.Points, from, step)
+		}
+		result = append(result, *series)
+	}
+
+	return result, nil
+}
+
+// Downsample averages sorted points, none before start, into buckets
+// of step size aligned to start. Each bucket is reported at its start
+// time and empty buckets are omitted.
+func Downsample(points []Point, start time.Time, step time.Duration) []Point {
+	var result []Point
+	var sum float64
+	var count int
+	bucket := time.Time{}
+	for _, p := range points {
+		b := start.Add(p.Timestamp.Sub(start).Truncate(step))
+		if !b.Equal(bucket) && count > 0 {
+			result = append(result, Point{Timestamp: bucket, Value: sum / float64(count)})
+			sum, count = 0, 0
+		}
+		bucket = b
+		sum += p.Value
+		count++
+	}
+	if count > 0 {
+		result = append(result, Point{Timestamp: bucket, Value: sum / float64(count)})
+	}
+	return result
+}
+
+// labelsMap returns lbls as a map, without the metric name label.
+func labelsMap(lbls labels.Labels) map[string]string {
+	m := make(map[string]string, lbls.Len())
+	lbls.Range(func(l labels.Label) {
+		if l.Name == DimensionLabelName {
+			return
+		}
+		m[l.Name] = l.Value
+	})
+	return m
+}
@@ -0,0 +1,156 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+func TestDownsample(t *testing.T) {
+	start := time.Date(2025, 1, 1, 8, 0, 30, 0, time.UTC)
+	at := func(d time.Duration, v float64) Point {
+		return Point{Timestamp: start.Add(d), Value: v}
+	}
+
+	tests := []struct {
+		name   string
+		points []Point
+		step   time.Duration
+		want   []Point
+	}{
+		{name: "no points", step: time.Minute},
+		{
+			name:   "single point is reported at its bucket start",
+			points: []Point{at(45*time.Second, 3)},
+			step:   time.Minute,
+			want:   []Point{at(0, 3)},
+		},
+		{
+			// buckets are aligned to start, not to the minute
+			name:   "average by bucket",
+			points: []Point{at(0, 1), at(20*time.Second, 2), at(59*time.Second, 3), at(time.Minute, 10), at(90*time.Second, 20)},
+			step:   time.Minute,
+			want:   []Point{at(0, 2), at(time.Minute, 15)},
+		},
+		{
+			name:   "empty buckets are omitted",
+			points: []Point{at(10*time.Second, 1), at(5*time.Minute, 7)},
+			step:   time.Minute,
+			want:   []Point{at(0, 1), at(5*time.Minute, 7)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Downsample(tt.points, start, tt.step)
+			if !slices.EqualFunc(got, tt.want, func(a, b Point) bool {
+				return a.Timestamp.Equal(b.Timestamp) && a.Value == b.Value
+			}) {
+				t.Errorf("Downsample() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterMatcher(t *testing.T) {
+	tests := []struct {
+		metrics []string
+		matches map[string]bool
+	}{
+		{
+			metrics: nil,
+			matches: map[string]bool{temperature: true, cpuUsage: true, "": false},
+		},
+		{
+			metrics: []string{temperature, cpuUsage},
+			matches: map[string]bool{temperature: true, cpuUsage: true, cpuCoreUsage: false, sensorTemperature: false},
+		},
+		{
+			// names are matched literally, not as regular expressions
+			metrics: []string{"cpu_.*"},
+			matches: map[string]bool{cpuUsage: false, "cpu_.*": true},
+		},
+	}
+	for _, tt := range tests {
+		m := Filter{Metrics: tt.metrics}.matcher()
+		for name, want := range tt.matches {
+			if got := m.Matches(name); got != want {
+				t.Errorf("Filter{Metrics: %q} matches %q = %v, want %v", tt.metrics, name, got, want)
+			}
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	for i, temp := range []float64{40, 42, 44, 50} {
+		r := model.ProbesResult{Timestamp: start.Add(time.Duration(i) * 20 * time.Second)}
+		r.Temp.Temperature = temp
+		r.CPU.CPUUsage = 10 * float64(i)
+		if err := store.Persist(ctx, &r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.PersistEvent(ctx, model.EventResult{Type: model.EventShutdown, Timestamp: start.Add(10 * time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   map[time.Duration][2]float64 // temperature and cpu usage by offset from start
+	}{
+		{
+			name:   "raw samples of the selected metric",
+			filter: Filter{Metrics: []string{temperature}},
+			want: map[time.Duration][2]float64{
+				0: {40, 0}, 20 * time.Second: {42, 0}, 40 * time.Second: {44, 0}, time.Minute: {50, 0},
+			},
+		},
+		{
+			name:   "averaged by step from the range start",
+			filter: Filter{From: start, To: start.Add(time.Hour), Step: time.Minute},
+			want: map[time.Duration][2]float64{
+				0: {42, 10}, time.Minute: {50, 30},
+			},
+		},
+		{
+			name:   "time range",
+			filter: Filter{From: start.Add(30 * time.Second), To: start.Add(50 * time.Second)},
+			want: map[time.Duration][2]float64{
+				40 * time.Second: {44, 20},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := store.Get(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != len(tt.want) {
+				t.Fatalf("Get() returned %d measurements, want %d", len(results), len(tt.want))
+			}
+			for _, r := range results {
+				want, ok := tt.want[r.Timestamp.Sub(start)]
+				if !ok {
+					t.Errorf("unexpected measurement at +%v", r.Timestamp.Sub(start))
+					continue
+				}
+				if got := [2]float64{r.Temp.Temperature, r.CPU.CPUUsage}; got != want {
+					t.Errorf("measurement at +%v = %v, want %v", r.Timestamp.Sub(start), got, want)
+				}
+			}
+		})
+	}
+
+	if _, err := store.Get(ctx, Filter{From: start, To: start.Add(-time.Second)}); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("Get() with an inverted range error = %v, want %v", err, ErrInvalidRange)
+	}
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+var (
+	ErrInvalidInterval = errors.New("sampling interval must be greater than zero")
+)
+
+// Job is the unit of work executed on every tick.
+type Job func(ctx context.Context) error
+
+// Scheduler runs a Job at a fixed interval until its context is cancelled.
+//
+// Ticks are aligned to the moment Run is called, so a slow Job doesn't
+// make the schedule drift. When a Job takes longer than the interval
+// the ticks that were missed in the meantime are skipped instead of
+// being executed back to back.
+type Scheduler struct {
+	interval time.Duration
+	jitter   time.Duration
+	job      Job
+}
+
+// New creates a new Scheduler. Each execution is delayed by a random
+// value in [0, jitter) so many agents started together don't sample
+// at the exact same instant.
+func New(interval, jitter time.Duration, job Job) *Scheduler {
+	return &Scheduler{
+		interval: interval,
+		jitter:   jitter,
+		job:      job,
+	}
+}
+
+// Run executes the job immediately and then on every tick. It blocks
+// until ctx is done and returns nil on a graceful shutdown.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	log := slog.With(
+		slog.String("interval", s.interval.String()),
+		slog.String("jitter", s.jitter.String()),
+	)
+	log.InfoContext(ctx, "starting scheduler")
+
+	next := time.Now()
+	for {
+		if err := sleep(ctx, time.Until(next)+s.randomJitter()); err != nil {
+			log.InfoContext(ctx, "stopping scheduler")
+			return nil
+		}
+
+		start := time.Now()
+		if err := s.job(ctx); err != nil {
+			log.With("error", err).ErrorContext(ctx, "failed to execute scheduled job")
+		}
+		log.With("elapsed", time.Since(start).String()).DebugContext(ctx, "scheduled job finished")
+
+		next = s.nextTick(next, time.Now())
+	}
+}
+
+// nextTick returns the first tick after now, logging the ticks that
+// were skipped because the previous execution overran.
+func (s *Scheduler) nextTick(prev, now time.Time) time.Time {
+	next := prev.Add(s.interval)
+	if now.Before(next) {
+		return next
+	}
+
+	missed := int64(now.Sub(next)/s.interval) + 1
+	slog.With(
+		slog.Int64("missed_ticks", missed),
+		slog.String("interval", s.interval.String()),
+	).Warn("scheduled job took longer than the interval, skipping missed ticks")
+
+	return next.Add(time.Duration(missed) * s.interval)
+}
+
+func (s *Scheduler) randomJitter() time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+	return rand.N(s.jitter)
+}
+
+// sleep waits for d or until ctx is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestNextTick(t *testing.T) {
+	s := New(10*time.Second, 0, nil)
+	prev := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		now  time.Duration
+		want time.Duration
+	}{
+		{name: "job finished early", now: time.Second, want: 10 * time.Second},
+		{name: "job finished right before the tick", now: 10*time.Second - time.Nanosecond, want: 10 * time.Second},
+		{name: "job finished on the tick", now: 10 * time.Second, want: 20 * time.Second},
+		{name: "job overran two ticks", now: 25 * time.Second, want: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ticks stay aligned to prev, whatever the job duration
+			if got := s.nextTick(prev, prev.Add(tt.now)); !got.Equal(prev.Add(tt.want)) {
+				t.Errorf("nextTick() = +%v, want +%v", got.Sub(prev), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSkipsMissedTicks(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 45*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		var starts []time.Duration
+		s := New(10*time.Millisecond, 0, func(ctx context.Context) error {
+			starts = append(starts, time.Since(start))
+			if len(starts) == 1 {
+				// the first execution overruns two ticks
+				time.Sleep(25 * time.Millisecond)
+			}
+			return errors.New("job errors don't stop the scheduler")
+		})
+		if err := s.Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		want := []time.Duration{0, 30 * time.Millisecond, 40 * time.Millisecond}
+		if !slices.Equal(starts, want) {
+			t.Errorf("job executed at %v, want %v", starts, want)
+		}
+	})
+}
+
+func TestRunJitter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 59*time.Second)
+		defer cancel()
+
+		start := time.Now()
+		var starts []time.Duration
+		s := New(10*time.Second, 2*time.Second, func(ctx context.Context) error {
+			starts = append(starts, time.Since(start))
+			return nil
+		})
+		if err := s.Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		for i, got := range starts {
+			tick := time.Duration(i) * 10 * time.Second
+			if got < tick || got >= tick+2*time.Second {
+				t.Errorf("execution %d at %v, want in [%v, %v)", i, got, tick, tick+2*time.Second)
+			}
+		}
+		if len(starts) != 6 {
+			t.Errorf("job executed %d times in a minute, want 6", len(starts))
+		}
+	})
+}
+
+func TestRunInvalidInterval(t *testing.T) {
+	err := New(0, 0, nil).Run(context.Background())
+	if !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("Run() error = %v, want %v", err, ErrInvalidInterval)
+	}
+}
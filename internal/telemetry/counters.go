@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// Counters keeps the cumulative counters read by the previous
+// measurement, so the rates cover the whole interval between two
+// measurements. The zero value is ready to use, the first measurement
+// then reads the counters twice, a sampling window apart.
+type Counters struct {
+	disk reading[map[string]disk.IOCountersStat]
+	net  reading[[]net.IOCountersStat]
+	swap reading[*mem.SwapMemoryStat]
+}
+
+type reading[T any] struct {
+	values T
+	at     time.Time
+	ok     bool
+}
+
+// readCounters reads the counters with read, returning them along with
+// the previous reading kept in last, which is replaced by the new one.
+func readCounters[T any](ctx context.Context, last *reading[T], read func(ctx context.Context) (T, error)) (before, after reading[T], err error) {
+	if !last.ok {
+		values, err := read(ctx)
+		if err != nil {
+			return before, after, err
+		}
+		*last = reading[T]{values: values, at: time.Now(), ok: true}
+		if !waitSamplingWindow(ctx) {
+			return before, after, ctx.Err()
+		}
+	}
+
+	values, err := read(ctx)
+	if err != nil {
+		return before, after, err
+	}
+	before, after = *last, reading[T]{values: values, at: time.Now(), ok: true}
+	*last = after
+	return before, after, nil
+}
+
+// counterDeltas computes the increments of counters, noting when any of
+// them went backwards because it was reset (e.g. the device was
+// re-created) or wrapped, which would otherwise be reported as a huge
+// rate.
+type counterDeltas struct {
+	reset bool
+}
+
+func (d *counterDeltas) of(before, after uint64) float64 {
+	if after < before {
+		d.reset = true
+		return 0
+	}
+	return float64(after - before)
+}
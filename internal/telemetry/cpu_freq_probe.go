@@ -0,0 +1,79 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+const (
+	cpuSysfsPath = "/sys/devices/system/cpu"
+)
+
+// measureCPUFrequencies reads the cpufreq state of each logical CPU.
+// It returns nil when the kernel doesn't expose cpufreq, as in most
+// containers and virtual machines.
+func measureCPUFrequencies(ctx context.Context) []model.CPUFrequencyResult {
+	dirs, err := filepath.Glob(filepath.Join(cpuSysfsPath, "cpu[0-9]*", "cpufreq"))
+	if err != nil || len(dirs) == 0 {
+		slog.With("error", err, "path", cpuSysfsPath).DebugContext(ctx, "cpufreq not available")
+		return nil
+	}
+
+	var result []model.CPUFrequencyResult
+	for _, dir := range dirs {
+		core, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(filepath.Dir(dir)), "cpu"))
+		if err != nil {
+			continue
+		}
+		freq := model.CPUFrequencyResult{Core: core}
+
+		// cpuinfo_cur_freq is the clock read from the hardware, but
+		// it's only readable by root
+		if freq.Current, err = readKHz(dir, "scaling_cur_freq", "cpuinfo_cur_freq"); err != nil {
+			slog.With("error", err, "path", dir).ErrorContext(ctx, "failed to get CPU frequency")
+			continue
+		}
+		if freq.Min, err = readKHz(dir, "scaling_min_freq", "cpuinfo_min_freq"); err != nil {
+			slog.With("error", err, "path", dir).ErrorContext(ctx, "failed to get CPU min frequency")
+		}
+		if freq.Max, err = readKHz(dir, "scaling_max_freq", "cpuinfo_max_freq"); err != nil {
+			slog.With("error", err, "path", dir).ErrorContext(ctx, "failed to get CPU max frequency")
+		}
+		if governor, err := os.ReadFile(filepath.Join(dir, "scaling_governor")); err == nil {
+			freq.Governor = strings.TrimSpace(string(governor))
+		}
+		result = append(result, freq)
+	}
+
+	slices.SortFunc(result, func(a, b model.CPUFrequencyResult) int {
+		return a.Core - b.Core
+	})
+	return result
+}
+
+// readKHz reads the first of files found in dir, converting its value
+// from kHz to Hz.
+func readKHz(dir string, files ...string) (int64, error) {
+	var errs []error
+	for _, f := range files {
+		b, err := os.ReadFile(filepath.Join(dir, f))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		v, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return v * 1000, nil
+	}
+	return 0, errors.Join(errs...)
+}
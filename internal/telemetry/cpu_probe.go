@@ -3,7 +3,7 @@ package telemetry
 import (
 	"context"
 	"log/slog"
-	"time"
+	"sync"
 
 	"github.com/eldius/rpi-system-monitor/internal/model"
 	"github.com/shirou/gopsutil/v3/cpu"
@@ -17,7 +17,26 @@ func measureCPU(ctx context.Context) model.CPUResult {
 		return result
 	}
 	result.CPUCount = int64(cpuCount)
-	percentages, err := cpu.Percent(time.Second, false)
+	result.Frequencies = measureCPUFrequencies(ctx)
+
+	// the samplings wait for a second, so they run side by side
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		result.Times = measureCPUTimes(ctx)
+	})
+	wg.Go(func() {
+		perCore, err := cpu.PercentWithContext(ctx, samplingWindow, true)
+		if err != nil {
+			slog.With("error", err).ErrorContext(ctx, "failed to get per core CPU usage")
+			return
+		}
+		for i, p := range perCore {
+			result.Cores = append(result.Cores, model.CPUCoreResult{Core: i, Usage: p})
+		}
+	})
+
+	percentages, err := cpu.PercentWithContext(ctx, samplingWindow, false)
+	wg.Wait()
 	if err != nil {
 		slog.With("error", err).ErrorContext(ctx, "failed to get CPU usage")
 		return result
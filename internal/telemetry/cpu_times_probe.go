@@ -0,0 +1,63 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// measureCPUTimes samples the aggregated CPU times twice, a sampling
+// window apart, and reports how the elapsed time was split between
+// modes.
+func measureCPUTimes(ctx context.Context) []model.CPUTimeResult {
+	before, err := cpu.TimesWithContext(ctx, false)
+	if err != nil || len(before) == 0 {
+		slog.With("error", err).ErrorContext(ctx, "failed to get CPU times")
+		return nil
+	}
+
+	if !waitSamplingWindow(ctx) {
+		return nil
+	}
+
+	after, err := cpu.TimesWithContext(ctx, false)
+	if err != nil || len(after) == 0 {
+		slog.With("error", err).ErrorContext(ctx, "failed to get CPU times")
+		return nil
+	}
+
+	return cpuTimesDelta(before[0], after[0])
+}
+
+func cpuTimesDelta(before, after cpu.TimesStat) []model.CPUTimeResult {
+	// guest time is already accounted in user time, so it's left out
+	deltas := map[string]float64{
+		"user":    after.User - before.User,
+		"nice":    after.Nice - before.Nice,
+		"system":  after.System - before.System,
+		"idle":    after.Idle - before.Idle,
+		"iowait":  after.Iowait - before.Iowait,
+		"irq":     after.Irq - before.Irq,
+		"softirq": after.Softirq - before.Softirq,
+		"steal":   after.Steal - before.Steal,
+	}
+
+	var total float64
+	for _, d := range deltas {
+		total += max(d, 0)
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	result := make([]model.CPUTimeResult, 0, len(model.CPUTimeModes))
+	for _, mode := range model.CPUTimeModes {
+		result = append(result, model.CPUTimeResult{
+			Mode:       mode,
+			Percentage: max(deltas[mode], 0) / total * 100,
+		})
+	}
+	return result
+}
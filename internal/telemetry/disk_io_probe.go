@@ -0,0 +1,81 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+var (
+	// virtualDevicePrefixes are the block devices left out of the
+	// disk I/O probe
+	virtualDevicePrefixes = []string{"loop", "ram"}
+)
+
+// measureDiskIO reports the throughput, IOPS, average wait and
+// utilization of the block devices since the previous measurement.
+func measureDiskIO(ctx context.Context, counters *Counters) []model.DiskIOResult {
+	before, after, err := readCounters(ctx, &counters.disk, func(ctx context.Context) (map[string]disk.IOCountersStat, error) {
+		return disk.IOCountersWithContext(ctx)
+	})
+	if err != nil {
+		slog.With("error", err).ErrorContext(ctx, "failed to get disk I/O counters")
+		return nil
+	}
+	elapsed := after.at.Sub(before.at)
+
+	var result []model.DiskIOResult
+	for name, a := range after.values {
+		b, ok := before.values[name]
+		if !ok || isVirtualDevice(name) {
+			continue
+		}
+		r, ok := diskIODelta(name, b, a, elapsed)
+		if !ok {
+			slog.With("device", name).DebugContext(ctx, "disk I/O counters were reset, skipping the device")
+			continue
+		}
+		result = append(result, r)
+	}
+
+	slices.SortFunc(result, func(a, b model.DiskIOResult) int {
+		return strings.Compare(a.Device, b.Device)
+	})
+	return result
+}
+
+// diskIODelta computes the rates of device between two readings, false
+// when its counters were reset in between.
+func diskIODelta(device string, before, after disk.IOCountersStat, elapsed time.Duration) (model.DiskIOResult, bool) {
+	var d counterDeltas
+	seconds := elapsed.Seconds()
+	reads := d.of(before.ReadCount, after.ReadCount)
+	writes := d.of(before.WriteCount, after.WriteCount)
+
+	result := model.DiskIOResult{
+		Device:                device,
+		ReadBytesPerSecond:    d.of(before.ReadBytes, after.ReadBytes) / seconds,
+		WriteBytesPerSecond:   d.of(before.WriteBytes, after.WriteBytes) / seconds,
+		ReadIOPS:              reads / seconds,
+		WriteIOPS:             writes / seconds,
+		UtilizationPercentage: min(d.of(before.IoTime, after.IoTime)/float64(elapsed.Milliseconds())*100, 100),
+	}
+	// read and write times are the milliseconds spent by all the
+	// requests, from queueing until they're done
+	wait := d.of(before.ReadTime, after.ReadTime) + d.of(before.WriteTime, after.WriteTime)
+	if ios := reads + writes; ios > 0 {
+		result.AwaitMilliseconds = wait / ios
+	}
+	return result, !d.reset
+}
+
+func isVirtualDevice(name string) bool {
+	return slices.ContainsFunc(virtualDevicePrefixes, func(prefix string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
@@ -0,0 +1,82 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// measureFilesystems reads the usage of the mounted physical volumes
+// selected by the filesystem probe filters.
+func measureFilesystems(ctx context.Context) []model.FilesystemResult {
+	partitions, err := disk.PartitionsWithContext(ctx, false)
+	if err != nil {
+		slog.With("error", err).ErrorContext(ctx, "failed to list partitions")
+		return nil
+	}
+
+	var result []model.FilesystemResult
+	seen := make(map[string]bool)
+	// a mountpoint is listed once for every mount stacked on it, only the
+	// last one is visible and it's the one measured, the ones below would
+	// be reported as duplicated series
+	for _, p := range slices.Backward(partitions) {
+		if seen[p.Mountpoint] {
+			continue
+		}
+		seen[p.Mountpoint] = true
+		if !filesystemSelected(p) {
+			continue
+		}
+		usage, err := disk.UsageWithContext(ctx, p.Mountpoint)
+		if err != nil {
+			slog.With("error", err, "mountpoint", p.Mountpoint).ErrorContext(ctx, "failed to get filesystem usage")
+			continue
+		}
+		result = append(result, model.FilesystemResult{
+			Mountpoint:            p.Mountpoint,
+			Device:                p.Device,
+			FSType:                p.Fstype,
+			Total:                 int64(usage.Total),
+			Used:                  int64(usage.Used),
+			Free:                  int64(usage.Free),
+			UsagePercentage:       usage.UsedPercent,
+			InodesTotal:           int64(usage.InodesTotal),
+			InodesUsed:            int64(usage.InodesUsed),
+			InodesFree:            int64(usage.InodesFree),
+			InodesUsagePercentage: usage.InodesUsedPercent,
+		})
+	}
+
+	slices.SortFunc(result, func(a, b model.FilesystemResult) int {
+		return strings.Compare(a.Mountpoint, b.Mountpoint)
+	})
+	return result
+}
+
+func filesystemSelected(p disk.PartitionStat) bool {
+	if include := config.GetFilesystemIncludeFSTypes(); len(include) > 0 && !slices.Contains(include, p.Fstype) {
+		return false
+	}
+	if slices.Contains(config.GetFilesystemExcludeFSTypes(), p.Fstype) {
+		return false
+	}
+	if include := config.GetFilesystemIncludeMountpoints(); len(include) > 0 && !matchAny(include, p.Mountpoint) {
+		return false
+	}
+	return !matchAny(config.GetFilesystemExcludeMountpoints(), p.Mountpoint)
+}
+
+// matchAny reports whether name matches any of the path patterns.
+func matchAny(patterns []string, name string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	})
+}
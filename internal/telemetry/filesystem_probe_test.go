@@ -0,0 +1,38 @@
+package telemetry
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
+)
+
+func TestMeasureFilesystemsStackedMounts(t *testing.T) {
+	// /tmp is mounted twice, as left by a repeated `mount -a`
+	proc, err := filepath.Abs(filepath.Join("testdata", "proc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOST_PROC", proc)
+
+	filesystems := measureFilesystems(context.Background())
+
+	var mountpoints []string
+	for _, f := range filesystems {
+		mountpoints = append(mountpoints, f.Mountpoint)
+	}
+	if want := []string{"/", "/tmp"}; !slices.Equal(mountpoints, want) {
+		t.Fatalf("measured mountpoints %v, want %v", mountpoints, want)
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range persistence.Samples(&model.ProbesResult{Filesystems: filesystems}) {
+		if seen[s.Labels.String()] {
+			t.Errorf("duplicated series %s", s.Labels)
+		}
+		seen[s.Labels.String()] = true
+	}
+}
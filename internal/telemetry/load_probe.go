@@ -0,0 +1,30 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/shirou/gopsutil/v3/load"
+)
+
+func measureLoad(ctx context.Context) model.LoadResult {
+	var result model.LoadResult
+	avg, err := load.AvgWithContext(ctx)
+	if err != nil {
+		slog.With("error", err).ErrorContext(ctx, "failed to get load average")
+		return result
+	}
+	result.Load1 = avg.Load1
+	result.Load5 = avg.Load5
+	result.Load15 = avg.Load15
+
+	misc, err := load.MiscWithContext(ctx)
+	if err != nil {
+		slog.With("error", err).ErrorContext(ctx, "failed to get run queue")
+		return result
+	}
+	result.ProcsRunning = int64(misc.ProcsRunning)
+	result.ProcsBlocked = int64(misc.ProcsBlocked)
+	return result
+}
@@ -7,7 +7,7 @@ import (
 	"log/slog"
 )
 
-func measureMemory(ctx context.Context) model.MemoryResult {
+func measureMemory(ctx context.Context, counters *Counters) model.MemoryResult {
 	var result model.MemoryResult
 	vmem, err := mem.VirtualMemoryWithContext(ctx)
 	if err != nil {
@@ -17,5 +17,32 @@ func measureMemory(ctx context.Context) model.MemoryResult {
 	result.MemoryUsagePercentage = vmem.UsedPercent
 	result.UsedMemory = int64(vmem.Used)
 	result.TotalMemory = int64(vmem.Total)
+	result.AvailableMemory = int64(vmem.Available)
+	result.BuffersMemory = int64(vmem.Buffers)
+	result.CachedMemory = int64(vmem.Cached)
+	result.SharedMemory = int64(vmem.Shared)
+	result.DirtyMemory = int64(vmem.Dirty)
+	result.WritebackMemory = int64(vmem.WriteBack)
+
+	result.Zram = measureZram(ctx)
+
+	// swap in and out are counters, reported as rates since the previous
+	// measurement
+	before, after, err := readCounters(ctx, &counters.swap, mem.SwapMemoryWithContext)
+	if err != nil {
+		slog.With("error", err).ErrorContext(ctx, "failed to get swap usage")
+		return result
+	}
+	seconds := after.at.Sub(before.at).Seconds()
+
+	result.UsedSwap = int64(after.values.Used)
+	result.TotalSwap = int64(after.values.Total)
+	var d counterDeltas
+	swapIn := d.of(before.values.Sin, after.values.Sin) / seconds
+	swapOut := d.of(before.values.Sout, after.values.Sout) / seconds
+	if !d.reset {
+		result.SwapInBytesPerSecond = swapIn
+		result.SwapOutBytesPerSecond = swapOut
+	}
 	return result
 }
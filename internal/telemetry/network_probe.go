@@ -0,0 +1,87 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+const (
+	netSysfsPath = "/sys/class/net"
+)
+
+var (
+	// virtualInterfacePrefixes are the interfaces left out of the
+	// network probe
+	virtualInterfacePrefixes = []string{"lo", "veth"}
+)
+
+// measureNetwork reports the traffic of the interfaces since the
+// previous measurement.
+func measureNetwork(ctx context.Context, counters *Counters) []model.NetworkResult {
+	before, after, err := readCounters(ctx, &counters.net, func(ctx context.Context) ([]net.IOCountersStat, error) {
+		return net.IOCountersWithContext(ctx, true)
+	})
+	if err != nil {
+		slog.With("error", err).ErrorContext(ctx, "failed to get network counters")
+		return nil
+	}
+	seconds := after.at.Sub(before.at).Seconds()
+
+	var result []model.NetworkResult
+	for _, a := range after.values {
+		i := slices.IndexFunc(before.values, func(b net.IOCountersStat) bool {
+			return b.Name == a.Name
+		})
+		if i < 0 || isVirtualInterface(a.Name) {
+			continue
+		}
+		b := before.values[i]
+		var d counterDeltas
+		r := model.NetworkResult{
+			Interface:          a.Name,
+			Up:                 readString(filepath.Join(netSysfsPath, a.Name, "operstate")) == "up",
+			SpeedMbps:          linkSpeed(a.Name),
+			RxBytesPerSecond:   d.of(b.BytesRecv, a.BytesRecv) / seconds,
+			TxBytesPerSecond:   d.of(b.BytesSent, a.BytesSent) / seconds,
+			RxPacketsPerSecond: d.of(b.PacketsRecv, a.PacketsRecv) / seconds,
+			TxPacketsPerSecond: d.of(b.PacketsSent, a.PacketsSent) / seconds,
+			RxErrorsPerSecond:  d.of(b.Errin, a.Errin) / seconds,
+			TxErrorsPerSecond:  d.of(b.Errout, a.Errout) / seconds,
+			RxDropsPerSecond:   d.of(b.Dropin, a.Dropin) / seconds,
+			TxDropsPerSecond:   d.of(b.Dropout, a.Dropout) / seconds,
+		}
+		if d.reset {
+			slog.With("interface", a.Name).DebugContext(ctx, "network counters were reset, skipping the interface")
+			continue
+		}
+		result = append(result, r)
+	}
+
+	slices.SortFunc(result, func(a, b model.NetworkResult) int {
+		return strings.Compare(a.Interface, b.Interface)
+	})
+	return result
+}
+
+// linkSpeed returns the speed of the interface in Mb/s, which the
+// kernel reports as -1, or fails to read, when it's unknown.
+func linkSpeed(name string) int64 {
+	speed, err := strconv.ParseInt(readString(filepath.Join(netSysfsPath, name, "speed")), 10, 64)
+	if err != nil || speed < 0 {
+		return 0
+	}
+	return speed
+}
+
+func isVirtualInterface(name string) bool {
+	return slices.ContainsFunc(virtualInterfacePrefixes, func(prefix string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
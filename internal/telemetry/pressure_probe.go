@@ -0,0 +1,94 @@
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+const (
+	pressurePath = "/proc/pressure"
+)
+
+// measurePressure reads the Pressure Stall Information of the CPU,
+// memory and I/O. It returns nil on kernels built without PSI.
+func measurePressure(ctx context.Context) []model.PressureResult {
+	var result []model.PressureResult
+	for _, resource := range model.PressureResources {
+		path := filepath.Join(pressurePath, resource)
+		lines, err := readPressure(resource, path)
+		var numErr *strconv.NumError
+		switch {
+		case errors.As(err, &numErr):
+			slog.With("error", err, "path", path).WarnContext(ctx, "skipping malformed pressure stall information")
+		case err != nil:
+			slog.With("error", err, "path", path).DebugContext(ctx, "pressure stall information not available")
+			continue
+		}
+		result = append(result, lines...)
+	}
+	return result
+}
+
+// readPressure parses a PSI file, made of lines such as
+// "some avg10=0.31 avg60=0.12 avg300=0.04 total=1534562". Malformed
+// lines are left out, rather than read as no pressure, and their
+// errors returned along with the other lines.
+func readPressure(resource, path string) ([]model.PressureResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var result []model.PressureResult
+	var errs []error
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		p, err := parsePressureFields(resource, fields)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parsing %q: %w", scanner.Text(), err))
+			continue
+		}
+		result = append(result, p)
+	}
+	errs = append(errs, scanner.Err())
+	return result, errors.Join(errs...)
+}
+
+func parsePressureFields(resource string, fields []string) (model.PressureResult, error) {
+	p := model.PressureResult{
+		Resource: resource,
+		Kind:     fields[0],
+	}
+	var err error
+	for _, field := range fields[1:] {
+		key, value, _ := strings.Cut(field, "=")
+		switch key {
+		case "avg10":
+			p.Avg10, err = strconv.ParseFloat(value, 64)
+		case "avg60":
+			p.Avg60, err = strconv.ParseFloat(value, 64)
+		case "avg300":
+			p.Avg300, err = strconv.ParseFloat(value, 64)
+		case "total":
+			p.TotalMicroseconds, err = strconv.ParseInt(value, 10, 64)
+		}
+		if err != nil {
+			return p, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return p, nil
+}
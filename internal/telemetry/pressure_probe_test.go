@@ -0,0 +1,56 @@
+package telemetry
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+func TestReadPressure(t *testing.T) {
+	tests := []struct {
+		resource string
+		file     string
+		want     []model.PressureResult
+		wantErr  bool
+	}{
+		{
+			resource: "memory",
+			file:     "memory",
+			want: []model.PressureResult{
+				{Resource: "memory", Kind: "some", Avg10: 0.31, Avg60: 0.12, Avg300: 0.04, TotalMicroseconds: 1534562},
+				{Resource: "memory", Kind: "full", Avg10: 0, Avg60: 0.01, Avg300: 0, TotalMicroseconds: 204811},
+			},
+		},
+		{
+			resource: "cpu",
+			file:     "cpu",
+			want: []model.PressureResult{
+				{Resource: "cpu", Kind: "some", Avg10: 2.04, Avg60: 1.5, Avg300: 0.87, TotalMicroseconds: 987654321},
+			},
+		},
+		{
+			// the malformed full line isn't reported as no pressure
+			resource: "io",
+			file:     "malformed",
+			want: []model.PressureResult{
+				{Resource: "io", Kind: "some", TotalMicroseconds: 83527102},
+			},
+			wantErr: true,
+		},
+		{resource: "io", file: "empty"},
+		{resource: "io", file: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got, err := readPressure(tt.resource, filepath.Join("testdata", "pressure", tt.file))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readPressure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readPressure() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package telemetry
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+const (
+	// maxCmdlineLength keeps long command lines from bloating the
+	// processes store
+	maxCmdlineLength = 256
+)
+
+type processUsage struct {
+	p       *process.Process
+	cpuTime float64
+	percent float64
+	rss     int64
+}
+
+// measureProcesses samples the CPU time of every process twice, a
+// sampling window apart, and returns the ones using the most CPU and
+// resident memory.
+func measureProcesses(ctx context.Context) *model.ProcessesResult {
+	limit := config.GetProcessesProbeTop()
+	if limit <= 0 {
+		return nil
+	}
+
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		slog.With("error", err).ErrorContext(ctx, "failed to list processes")
+		return nil
+	}
+	before := make(map[int32]float64, len(procs))
+	for _, p := range procs {
+		if t, err := p.TimesWithContext(ctx); err == nil {
+			before[p.Pid] = t.User + t.System
+		}
+	}
+	start := time.Now()
+
+	if !waitSamplingWindow(ctx) {
+		return nil
+	}
+
+	seconds := time.Since(start).Seconds()
+	var usages []processUsage
+	for _, p := range procs {
+		t, err := p.TimesWithContext(ctx)
+		if err != nil {
+			// the process is gone
+			continue
+		}
+		mem, err := p.MemoryInfoWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		u := processUsage{
+			p:       p,
+			cpuTime: t.User + t.System,
+			rss:     int64(mem.RSS),
+		}
+		if b, ok := before[p.Pid]; ok {
+			u.percent = max(u.cpuTime-b, 0) / seconds * 100
+		}
+		usages = append(usages, u)
+	}
+
+	selected := topUsages(usages, limit, func(a, b processUsage) int {
+		return cmp.Compare(b.percent, a.percent)
+	})
+	for _, u := range topUsages(usages, limit, func(a, b processUsage) int {
+		return cmp.Compare(b.rss, a.rss)
+	}) {
+		if !slices.ContainsFunc(selected, func(s processUsage) bool { return s.p.Pid == u.p.Pid }) {
+			selected = append(selected, u)
+		}
+	}
+
+	result := &model.ProcessesResult{Limit: limit}
+	for _, u := range selected {
+		result.Processes = append(result.Processes, processResult(ctx, u))
+	}
+	return result
+}
+
+func topUsages(usages []processUsage, limit int, compare func(a, b processUsage) int) []processUsage {
+	sorted := slices.SortedStableFunc(slices.Values(usages), compare)
+	return sorted[:min(limit, len(sorted))]
+}
+
+// processResult reads the details of a selected process, which are
+// left empty when the process exits in the meantime.
+func processResult(ctx context.Context, u processUsage) model.ProcessResult {
+	result := model.ProcessResult{
+		PID:           u.p.Pid,
+		CPUPercentage: u.percent,
+		RSS:           u.rss,
+	}
+	result.Name, _ = u.p.NameWithContext(ctx)
+	result.User, _ = u.p.UsernameWithContext(ctx)
+	result.Threads, _ = u.p.NumThreadsWithContext(ctx)
+	if cmdline, err := u.p.CmdlineWithContext(ctx); err == nil {
+		result.Cmdline = truncate(strings.TrimSpace(cmdline), maxCmdlineLength)
+	}
+	return result
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "") + "…"
+}
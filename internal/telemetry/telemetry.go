@@ -2,17 +2,32 @@ package telemetry
 
 import (
 	"context"
+	"github.com/eldius/rpi-system-monitor/internal/config"
 	"github.com/eldius/rpi-system-monitor/internal/feature_toggle"
 	"github.com/eldius/rpi-system-monitor/internal/model"
+	"os/exec"
+	"strings"
 	"sync"
 	"time"
 )
 
 const (
 	temperatureURI = "/sys/class/thermal/thermal_zone0/temp"
+
+	// samplingWindow is the time between the two readings of the
+	// probes reporting rates, the same used to measure CPU usage. The
+	// counter based probes only wait for it on their first measurement,
+	// as they compute the rates since the previous one afterwards
+	samplingWindow = time.Second
+
+	// commandTimeout limits the time waiting for external commands
+	commandTimeout = 2 * time.Second
 )
 
-func Measure(ctx context.Context) model.ProbesResult {
+// Measure takes a measurement of every enabled probe. The rates read
+// from cumulative counters are computed since the measurement kept in
+// counters, which is updated.
+func Measure(ctx context.Context, counters *Counters) model.ProbesResult {
 
 	var result model.ProbesResult
 
@@ -22,12 +37,71 @@ func Measure(ctx context.Context) model.ProbesResult {
 	})
 
 	wg.Go(func() {
-		result.Memory = measureMemory(ctx)
+		result.Memory = measureMemory(ctx, counters)
+	})
+
+	wg.Go(func() {
+		result.Load = measureLoad(ctx)
+	})
+
+	wg.Go(func() {
+		result.Pressure = measurePressure(ctx)
+	})
+
+	wg.Go(func() {
+		result.Uptime = measureUptime(ctx)
+	})
+
+	wg.Go(func() {
+		result.DiskIO = measureDiskIO(ctx, counters)
+	})
+
+	wg.Go(func() {
+		result.Network = measureNetwork(ctx, counters)
 	})
 
 	_ = feature_toggle.FeatureToggle(ctx, "monitor.server.temperature_probe.enabled", func(ctx context.Context) error {
 		wg.Go(func() {
 			result.Temp = measureTemperature()
+			result.Temp.Sensors = measureTemperatureSensors(ctx)
+		})
+		return nil
+	})
+
+	// the 1-Wire sensors are read apart from the temperature probe, as
+	// they're slower, and merged into its sensors once both are done
+	var w1Sensors []model.TemperatureSensorResult
+	_ = feature_toggle.FeatureToggle(ctx, config.W1ProbeEnabledProp.Key, func(ctx context.Context) error {
+		wg.Go(func() {
+			w1Sensors = measureW1Sensors(ctx)
+		})
+		return nil
+	})
+
+	_ = feature_toggle.FeatureToggle(ctx, config.ThrottlingProbeEnabledProp.Key, func(ctx context.Context) error {
+		wg.Go(func() {
+			result.Throttling = measureThrottling(ctx)
+		})
+		return nil
+	})
+
+	_ = feature_toggle.FeatureToggle(ctx, config.FilesystemProbeEnabledProp.Key, func(ctx context.Context) error {
+		wg.Go(func() {
+			result.Filesystems = measureFilesystems(ctx)
+		})
+		return nil
+	})
+
+	_ = feature_toggle.FeatureToggle(ctx, config.WirelessProbeEnabledProp.Key, func(ctx context.Context) error {
+		wg.Go(func() {
+			result.Wireless = measureWireless(ctx)
+		})
+		return nil
+	})
+
+	_ = feature_toggle.FeatureToggle(ctx, config.ProcessesProbeEnabledProp.Key, func(ctx context.Context) error {
+		wg.Go(func() {
+			result.Processes = measureProcesses(ctx)
 		})
 		return nil
 	})
@@ -36,5 +110,30 @@ func Measure(ctx context.Context) model.ProbesResult {
 
 	wg.Wait()
 
+	result.Temp.Sensors = append(result.Temp.Sensors, w1Sensors...)
+
 	return result
 }
+
+// waitSamplingWindow waits for the sampling window, returning false if
+// ctx is done before it.
+func waitSamplingWindow(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(samplingWindow):
+		return true
+	}
+}
+
+// runCommand runs command, returning its trimmed output.
+func runCommand(ctx context.Context, command string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, command, args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+const (
+	thermalPath = "/sys/class/thermal"
+	hwmonPath   = "/sys/class/hwmon"
+)
+
+// measureTemperatureSensors reads every thermal zone and hwmon
+// temperature input exposed by the kernel.
+func measureTemperatureSensors(ctx context.Context) []model.TemperatureSensorResult {
+	var result []model.TemperatureSensorResult
+
+	zones, _ := filepath.Glob(filepath.Join(thermalPath, "thermal_zone*"))
+	for _, zone := range zones {
+		temp, err := readMilliCelsius(filepath.Join(zone, "temp"))
+		if err != nil {
+			slog.With("error", err, "path", zone).DebugContext(ctx, "failed to read thermal zone")
+			continue
+		}
+		result = append(result, model.TemperatureSensorResult{
+			Sensor:      filepath.Base(zone),
+			Chip:        readString(filepath.Join(zone, "type")),
+			Temperature: temp,
+		})
+	}
+
+	result = append(result, readHwmonSensors(ctx, hwmonPath)...)
+
+	return result
+}
+
+// readHwmonSensors reads the temperature inputs of the hwmon chips in
+// root. The hwmonN directories are numbered in the order the drivers
+// were probed, which changes across reboots, so sensors are named after
+// the chip and the input instead, e.g. "cpu_thermal/temp1", adding the
+// device of the chips sharing a name, e.g. "nvme/nvme0/temp1". Chips
+// without a name are named after their device, and the ones that can't
+// be told apart without the hwmonN index are skipped.
+func readHwmonSensors(ctx context.Context, root string) []model.TemperatureSensorResult {
+	dirs, _ := filepath.Glob(filepath.Join(root, "hwmon*"))
+	chips := make(map[string]string, len(dirs))
+	shared := make(map[string]int)
+	for _, dir := range dirs {
+		chip := readString(filepath.Join(dir, "name"))
+		chips[dir] = chip
+		shared[chip]++
+	}
+
+	var result []model.TemperatureSensorResult
+	for _, dir := range dirs {
+		chip := chips[dir]
+		prefix := chip
+		if chip == "" || shared[chip] > 1 {
+			device, err := os.Readlink(filepath.Join(dir, "device"))
+			if err != nil {
+				slog.With("error", err, "path", dir, "chip", chip).DebugContext(ctx, "skipping hwmon chip without a stable name")
+				continue
+			}
+			prefix = strings.TrimPrefix(chip+"/"+filepath.Base(device), "/")
+		}
+
+		inputs, _ := filepath.Glob(filepath.Join(dir, "temp*_input"))
+		for _, input := range inputs {
+			temp, err := readMilliCelsius(input)
+			if err != nil {
+				slog.With("error", err, "path", input).DebugContext(ctx, "failed to read hwmon sensor")
+				continue
+			}
+			name := strings.TrimSuffix(filepath.Base(input), "_input")
+			result = append(result, model.TemperatureSensorResult{
+				Sensor:      prefix + "/" + name,
+				Chip:        chip,
+				Label:       readString(filepath.Join(dir, name+"_label")),
+				Temperature: temp,
+			})
+		}
+	}
+
+	return result
+}
+
+func readMilliCelsius(path string) (float64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return float64(v) / 1000.0, nil
+}
+
+// readString returns the trimmed content of path, or an empty string
+// when it can't be read.
+func readString(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
@@ -0,0 +1,25 @@
+package telemetry
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+func TestReadHwmonSensors(t *testing.T) {
+	want := []model.TemperatureSensorResult{
+		{Sensor: "cpu_thermal/temp1", Chip: "cpu_thermal", Temperature: 48.25},
+		{Sensor: "nvme/nvme1/temp1", Chip: "nvme", Label: "Composite", Temperature: 38.85},
+		{Sensor: "nvme/nvme0/temp1", Chip: "nvme", Label: "Composite", Temperature: 35.85},
+		{Sensor: "nvme/nvme0/temp2", Chip: "nvme", Label: "Sensor 1", Temperature: 41.85},
+		{Sensor: "1-0048/temp1", Temperature: 51},
+	}
+
+	got := readHwmonSensors(context.Background(), filepath.Join("testdata", "hwmon"))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readHwmonSensors() =\n%+v\nwant\n%+v", got, want)
+	}
+}
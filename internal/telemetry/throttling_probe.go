@@ -0,0 +1,99 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+const (
+	// get_throttled bits, the "occurred" ones are the same conditions
+	// shifted by occurredShift and are only cleared on reboot
+	underVoltageBit         = 1 << 0
+	armFrequencyCappedBit   = 1 << 1
+	throttledBit            = 1 << 2
+	softTemperatureLimitBit = 1 << 3
+	occurredShift           = 16
+)
+
+var (
+	voltageRails = []string{"core", "sdram_c", "sdram_i", "sdram_p"}
+)
+
+// measureThrottling reads the throttling state and rail voltages with
+// vcgencmd. It returns nil when the command isn't installed or can't be
+// run, e.g. without access to /dev/vchiq, which is only logged at debug
+// level as it fails the same way on every measurement.
+func measureThrottling(ctx context.Context) *model.ThrottlingResult {
+	command := config.GetThrottlingProbeCommand()
+
+	out, err := runCommand(ctx, command, "get_throttled")
+	if errors.Is(err, exec.ErrNotFound) {
+		slog.With("error", err, "command", command).DebugContext(ctx, "vcgencmd not available")
+		return nil
+	}
+	if err != nil {
+		slog.With("error", err, "command", command).DebugContext(ctx, "failed to get throttling state")
+		return nil
+	}
+	flags, err := parseThrottled(out)
+	if err != nil {
+		slog.With("error", err, "output", out).DebugContext(ctx, "failed to parse throttling state")
+		return nil
+	}
+
+	result := &model.ThrottlingResult{
+		Now:      decodeThrottled(flags),
+		Occurred: decodeThrottled(flags >> occurredShift),
+	}
+
+	for _, rail := range voltageRails {
+		out, err := runCommand(ctx, command, "measure_volts", rail)
+		if err != nil {
+			slog.With("error", err, "rail", rail).DebugContext(ctx, "failed to measure voltage")
+			continue
+		}
+		volts, err := parseVolts(out)
+		if err != nil {
+			slog.With("error", err, "output", out).DebugContext(ctx, "failed to parse voltage")
+			continue
+		}
+		result.Voltages = append(result.Voltages, model.VoltageResult{Rail: rail, Volts: volts})
+	}
+
+	return result
+}
+
+// parseThrottled parses the "throttled=0x50005" output of get_throttled.
+func parseThrottled(out string) (uint64, error) {
+	v, ok := strings.CutPrefix(out, "throttled=")
+	if !ok {
+		return 0, fmt.Errorf("unexpected output '%s'", out)
+	}
+	return strconv.ParseUint(v, 0, 64)
+}
+
+func decodeThrottled(flags uint64) model.ThrottlingFlags {
+	return model.ThrottlingFlags{
+		UnderVoltage:         flags&underVoltageBit != 0,
+		ArmFrequencyCapped:   flags&armFrequencyCappedBit != 0,
+		Throttled:            flags&throttledBit != 0,
+		SoftTemperatureLimit: flags&softTemperatureLimitBit != 0,
+	}
+}
+
+// parseVolts parses the "volt=0.8600V" output of measure_volts.
+func parseVolts(out string) (float64, error) {
+	v, ok := strings.CutPrefix(out, "volt=")
+	if !ok {
+		return 0, fmt.Errorf("unexpected output '%s'", out)
+	}
+	return strconv.ParseFloat(strings.TrimSuffix(v, "V"), 64)
+}
@@ -0,0 +1,144 @@
+package telemetry
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/spf13/viper"
+)
+
+func TestParseThrottled(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    uint64
+		wantErr bool
+	}{
+		{name: "not throttled", out: "throttled=0x0", want: 0},
+		{name: "under-voltage now and since boot", out: "throttled=0x50005", want: 0x50005},
+		{name: "occurred only", out: "throttled=0xe0000", want: 0xe0000},
+		{name: "missing prefix", out: "0x50005", wantErr: true},
+		{name: "error message", out: `error=1 error_msg="Command not registered"`, wantErr: true},
+		{name: "empty", out: "", wantErr: true},
+		{name: "not a number", out: "throttled=0xzz", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseThrottled(tt.out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseThrottled(%q) error = %v, wantErr %v", tt.out, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseThrottled(%q) = %#x, want %#x", tt.out, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeThrottled(t *testing.T) {
+	flags := uint64(0x50005)
+	if got, want := decodeThrottled(flags), (model.ThrottlingFlags{UnderVoltage: true, Throttled: true}); got != want {
+		t.Errorf("decodeThrottled(%#x) = %+v, want %+v", flags, got, want)
+	}
+	if got, want := decodeThrottled(flags>>occurredShift), (model.ThrottlingFlags{UnderVoltage: true, Throttled: true}); got != want {
+		t.Errorf("decodeThrottled(%#x >> %d) = %+v, want %+v", flags, occurredShift, got, want)
+	}
+}
+
+func TestParseVolts(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    float64
+		wantErr bool
+	}{
+		{name: "core", out: "volt=0.8600V", want: 0.86},
+		{name: "without unit", out: "volt=1.2", want: 1.2},
+		{name: "missing prefix", out: "0.8600V", wantErr: true},
+		{name: "error message", out: `error=2 error_msg="Invalid arguments"`, wantErr: true},
+		{name: "not a number", out: "volt=V", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVolts(tt.out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseVolts(%q) error = %v, wantErr %v", tt.out, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseVolts(%q) = %v, want %v", tt.out, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasureThrottling(t *testing.T) {
+	voltages := []model.VoltageResult{
+		{Rail: "core", Volts: 0.86},
+		{Rail: "sdram_c", Volts: 1.1},
+		{Rail: "sdram_i", Volts: 1.1},
+	}
+	tests := []struct {
+		name      string
+		command   string
+		throttled string
+		noVCHIQ   bool
+		want      *model.ThrottlingResult
+	}{
+		{
+			name:      "not throttled",
+			command:   "testdata/vcgencmd",
+			throttled: "throttled=0x0",
+			want:      &model.ThrottlingResult{Voltages: voltages},
+		},
+		{
+			name:      "under-voltage",
+			command:   "testdata/vcgencmd",
+			throttled: "throttled=0x50005",
+			want: &model.ThrottlingResult{
+				Now:      model.ThrottlingFlags{UnderVoltage: true, Throttled: true},
+				Occurred: model.ThrottlingFlags{UnderVoltage: true, Throttled: true},
+				Voltages: voltages,
+			},
+		},
+		{
+			name:      "missing throttled",
+			command:   "testdata/vcgencmd",
+			throttled: `error=1 error_msg="Command not registered"`,
+		},
+		{
+			name:    "no vchiq access",
+			command: "testdata/vcgencmd",
+			noVCHIQ: true,
+		},
+		{
+			name:    "command not installed",
+			command: "missing-vcgencmd",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := tt.command
+			if strings.Contains(command, "/") {
+				var err error
+				if command, err = filepath.Abs(command); err != nil {
+					t.Fatal(err)
+				}
+			}
+			viper.Set(config.ThrottlingProbeCommandProp.Key, command)
+			t.Cleanup(func() { viper.Set(config.ThrottlingProbeCommandProp.Key, nil) })
+			t.Setenv("VCGENCMD_THROTTLED", tt.throttled)
+			if tt.noVCHIQ {
+				t.Setenv("VCGENCMD_NO_VCHIQ", "1")
+			}
+
+			if got := measureThrottling(context.Background()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("measureThrottling() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
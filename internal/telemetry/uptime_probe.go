@@ -0,0 +1,59 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+const (
+	bootIDPath = "/proc/sys/kernel/random/boot_id"
+
+	systemctlCommand = "systemctl"
+
+	// systemStateStopping is reported by `systemctl is-system-running`
+	// while the system is shutting down
+	systemStateStopping = "stopping"
+)
+
+func measureUptime(ctx context.Context) model.UptimeResult {
+	var result model.UptimeResult
+	uptime, err := host.UptimeWithContext(ctx)
+	if err != nil {
+		slog.With("error", err).ErrorContext(ctx, "failed to get uptime")
+		return result
+	}
+	bootTime, err := host.BootTimeWithContext(ctx)
+	if err != nil {
+		slog.With("error", err).ErrorContext(ctx, "failed to get boot time")
+		return result
+	}
+	result.UptimeSeconds = int64(uptime)
+	result.BootTime = time.Unix(int64(bootTime), 0)
+	result.BootID = readString(bootIDPath)
+	return result
+}
+
+// SystemStopping reports whether the system is shutting down, as told
+// by systemd. Without systemd there's no way to tell the agent is being
+// stopped by a shutdown, so it's assumed not to be, as a shutdown
+// recorded by mistake would hide the next unexpected reboot.
+func SystemStopping(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	// is-system-running exits with an error for every state but
+	// running, so the output is checked regardless of it
+	out, err := exec.CommandContext(ctx, systemctlCommand, "is-system-running").Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		slog.With("command", systemctlCommand).DebugContext(ctx, "systemctl not found, can't tell whether the system is stopping")
+		return false
+	}
+	return strings.TrimSpace(string(out)) == systemStateStopping
+}
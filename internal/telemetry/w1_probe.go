@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+const (
+	// ds18b20Family is the 1-Wire family code prefixing the ID of the
+	// DS18B20 sensors
+	ds18b20Family = "28-"
+	ds18b20Chip   = "ds18b20"
+
+	// ds18b20PowerOnReset is the value of the scratchpad before the
+	// first conversion, read when a conversion fails (e.g. without
+	// enough power on parasite mode) even if the CRC matches
+	ds18b20PowerOnReset = 85000
+)
+
+var (
+	errW1CRC = errors.New("crc check failed")
+)
+
+// measureW1Sensors reads every DS18B20 sensor listed by the w1 kernel
+// driver. Each reading triggers a conversion taking up to 750ms, so
+// it's kept out of the thermal and hwmon sensors probe.
+func measureW1Sensors(ctx context.Context) []model.TemperatureSensorResult {
+	var result []model.TemperatureSensorResult
+
+	names := config.GetW1ProbeNames()
+	devices, _ := filepath.Glob(filepath.Join(config.GetW1ProbeDevicesPath(), ds18b20Family+"*"))
+	for _, dev := range devices {
+		if ctx.Err() != nil {
+			break
+		}
+		id := filepath.Base(dev)
+		path := filepath.Join(dev, "w1_slave")
+		temp, err := readW1Slave(path)
+		if errors.Is(err, errW1CRC) {
+			slog.With("path", path).WarnContext(ctx, "discarding DS18B20 reading with invalid crc, check the sensor wiring")
+			continue
+		}
+		if err != nil {
+			slog.With("error", err, "path", path).DebugContext(ctx, "failed to read DS18B20 sensor")
+			continue
+		}
+		result = append(result, model.TemperatureSensorResult{
+			Sensor:      "w1/" + id,
+			Chip:        ds18b20Chip,
+			Label:       names[strings.ToLower(id)],
+			Temperature: temp,
+		})
+	}
+
+	return result
+}
+
+// readW1Slave parses the w1_slave file of a DS18B20 sensor, in °C:
+//
+//	72 01 4b 46 7f ff 0e 10 57 : crc=57 YES
+//	72 01 4b 46 7f ff 0e 10 57 t=23125
+//
+// The reading is only valid when the first line ends with YES.
+func readW1Slave(path string) (float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return 0, fmt.Errorf("empty w1_slave: %w", scanner.Err())
+	}
+	if !strings.HasSuffix(strings.TrimSpace(scanner.Text()), "YES") {
+		return 0, errW1CRC
+	}
+	if !scanner.Scan() {
+		return 0, fmt.Errorf("missing temperature line: %w", scanner.Err())
+	}
+	_, raw, ok := strings.Cut(scanner.Text(), "t=")
+	if !ok {
+		return 0, fmt.Errorf("missing temperature in %q", scanner.Text())
+	}
+	v, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing temperature: %w", err)
+	}
+	if v == ds18b20PowerOnReset {
+		return 0, errors.New("power on reset value, the conversion failed")
+	}
+	return float64(v) / 1000.0, nil
+}
@@ -0,0 +1,63 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/spf13/viper"
+)
+
+func TestReadW1Slave(t *testing.T) {
+	tests := []struct {
+		file    string
+		want    float64
+		wantErr bool
+		wantCRC bool
+	}{
+		{file: "valid", want: 23.125},
+		{file: "negative", want: -1.25},
+		{file: "crc_no", wantErr: true, wantCRC: true},
+		{file: "power_on_reset", wantErr: true},
+		{file: "truncated", wantErr: true},
+		{file: "missing_temperature", wantErr: true},
+		{file: "empty", wantErr: true},
+		{file: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got, err := readW1Slave(filepath.Join("testdata", "w1", tt.file))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readW1Slave() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, errW1CRC) != tt.wantCRC {
+				t.Fatalf("readW1Slave() error = %v, want crc error %v", err, tt.wantCRC)
+			}
+			if got != tt.want {
+				t.Errorf("readW1Slave() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasureW1Sensors(t *testing.T) {
+	viper.Set(config.W1ProbeDevicesPathProp.Key, filepath.Join("testdata", "w1", "devices"))
+	viper.Set(config.W1ProbeNamesProp.Key, map[string]string{"28-0316a2795bff": "garage"})
+	t.Cleanup(func() {
+		viper.Set(config.W1ProbeDevicesPathProp.Key, nil)
+		viper.Set(config.W1ProbeNamesProp.Key, nil)
+	})
+
+	// the sensor failing the crc check is left out
+	want := []model.TemperatureSensorResult{
+		{Sensor: "w1/28-0316a2795bff", Chip: ds18b20Chip, Label: "garage", Temperature: 23.125},
+		{Sensor: "w1/28-0316a27b5678", Chip: ds18b20Chip, Temperature: -1.25},
+	}
+	if got := measureW1Sensors(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Errorf("measureW1Sensors() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,131 @@
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/eldius/rpi-system-monitor/internal/config"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+const (
+	wirelessPath = "/proc/net/wireless"
+
+	// unknownNoise is the noise level reported by drivers that don't
+	// measure it
+	unknownNoise = -256
+)
+
+// measureWireless reads the link quality of every wireless interface,
+// adding the SSID and bitrates reported by iw when it's configured.
+func measureWireless(ctx context.Context) []model.WirelessResult {
+	result, err := readWireless(wirelessPath)
+	if err != nil {
+		slog.With("error", err, "path", wirelessPath).DebugContext(ctx, "wireless statistics not available")
+		return nil
+	}
+	if command := config.GetWirelessProbeIwCommand(); command != "" {
+		for i := range result {
+			readIwLink(ctx, command, &result[i])
+		}
+	}
+	return result
+}
+
+// readWireless parses the interface lines of a /proc/net/wireless
+// file.
+func readWireless(path string) ([]model.WirelessResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var result []model.WirelessResult
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if w, ok := parseWirelessLine(scanner.Text()); ok {
+			result = append(result, w)
+		}
+	}
+	return result, scanner.Err()
+}
+
+// parseWirelessLine parses an interface line of /proc/net/wireless,
+// such as "wlan0: 0000   70.  -40.  -256  0  0  0  0  0  0", returning
+// false for the header lines.
+func parseWirelessLine(line string) (model.WirelessResult, bool) {
+	name, stats, ok := strings.Cut(line, ":")
+	if !ok {
+		return model.WirelessResult{}, false
+	}
+	fields := strings.Fields(stats)
+	if len(fields) < 4 {
+		return model.WirelessResult{}, false
+	}
+
+	values := make([]float64, 3)
+	for i, field := range fields[1:4] {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(field, "."), 64)
+		if err != nil {
+			return model.WirelessResult{}, false
+		}
+		values[i] = v
+	}
+
+	w := model.WirelessResult{
+		Interface:   strings.TrimSpace(name),
+		LinkQuality: values[0],
+		SignalDBm:   values[1],
+	}
+	if values[2] != unknownNoise {
+		w.NoiseDBm = values[2]
+	}
+	return w, true
+}
+
+// readIwLink fills the SSID and bitrates of w from the output of
+// `iw dev <if> link`.
+func readIwLink(ctx context.Context, command string, w *model.WirelessResult) {
+	out, err := runCommand(ctx, command, "dev", w.Interface, "link")
+	if errors.Is(err, exec.ErrNotFound) {
+		slog.With("error", err, "command", command).DebugContext(ctx, "iw not available")
+		return
+	}
+	if err != nil {
+		slog.With("error", err, "interface", w.Interface).ErrorContext(ctx, "failed to get wireless link")
+		return
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "SSID":
+			w.SSID = value
+		case "rx bitrate":
+			w.RxBitrateMbps = parseBitrate(value)
+		case "tx bitrate":
+			w.TxBitrateMbps = parseBitrate(value)
+		}
+	}
+}
+
+// parseBitrate parses bitrates such as "433.3 MBit/s VHT-MCS 9".
+func parseBitrate(v string) float64 {
+	fields := strings.Fields(v)
+	if len(fields) == 0 {
+		return 0
+	}
+	rate, _ := strconv.ParseFloat(fields[0], 64)
+	return rate
+}
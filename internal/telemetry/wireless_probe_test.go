@@ -0,0 +1,95 @@
+package telemetry
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+func TestParseWirelessLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   model.WirelessResult
+		wantOK bool
+	}{
+		{
+			name:   "unknown noise",
+			line:   " wlan0: 0000   70.  -40.  -256        0      0      0      0      0        0",
+			want:   model.WirelessResult{Interface: "wlan0", LinkQuality: 70, SignalDBm: -40},
+			wantOK: true,
+		},
+		{
+			name:   "with noise",
+			line:   " wlan1: 0000   45.  -65.  -92.        0      0      0      3      0        0",
+			want:   model.WirelessResult{Interface: "wlan1", LinkQuality: 45, SignalDBm: -65, NoiseDBm: -92},
+			wantOK: true,
+		},
+		{
+			name:   "without trailing dots",
+			line:   "wlan0: 0000 70 -40 -95 0 0 0 0 0 0",
+			want:   model.WirelessResult{Interface: "wlan0", LinkQuality: 70, SignalDBm: -40, NoiseDBm: -95},
+			wantOK: true,
+		},
+		{name: "header", line: "Inter-| sta-|   Quality        |   Discarded packets               | Missed | WE"},
+		{name: "header columns", line: " face | tus | link level noise |  nwid  crypt   frag  retry   misc | beacon | 22"},
+		{name: "too few fields", line: " wlan0: 0000   70.  -40."},
+		{name: "not a number", line: " wlan0: 0000   70.  abc.  -256"},
+		{name: "empty", line: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseWirelessLine(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("parseWirelessLine(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("parseWirelessLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadWireless(t *testing.T) {
+	got, err := readWireless(filepath.Join("testdata", "wireless"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.WirelessResult{
+		{Interface: "wlan0", LinkQuality: 70, SignalDBm: -40},
+		{Interface: "wlan1", LinkQuality: 45, SignalDBm: -65, NoiseDBm: -92},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readWireless() = %+v, want %+v", got, want)
+	}
+
+	// no wireless interface, only the headers are listed
+	if got, err := readWireless(filepath.Join("testdata", "wireless_none")); err != nil || got != nil {
+		t.Errorf("readWireless() = %+v, %v, want no interfaces", got, err)
+	}
+
+	if _, err := readWireless(filepath.Join("testdata", "missing")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("readWireless() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestParseBitrate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{in: "433.3 MBit/s VHT-MCS 9 80MHz short GI VHT-NSS 1", want: 433.3},
+		{in: "72.2 MBit/s", want: 72.2},
+		{in: "", want: 0},
+		{in: "unknown", want: 0},
+	}
+	for _, tt := range tests {
+		if got := parseBitrate(tt.in); got != tt.want {
+			t.Errorf("parseBitrate(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
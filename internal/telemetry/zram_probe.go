@@ -0,0 +1,50 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/eldius/rpi-system-monitor/internal/model"
+)
+
+const (
+	blockSysfsPath = "/sys/block"
+)
+
+// measureZram reads the usage of the initialized zram devices.
+func measureZram(ctx context.Context) []model.ZramResult {
+	dirs, _ := filepath.Glob(filepath.Join(blockSysfsPath, "zram*"))
+
+	var result []model.ZramResult
+	for _, dir := range dirs {
+		diskSize, err := strconv.ParseInt(readString(filepath.Join(dir, "disksize")), 10, 64)
+		if err != nil || diskSize == 0 {
+			// not initialized
+			continue
+		}
+
+		// mm_stat starts with orig_data_size, compr_data_size and
+		// mem_used_total, all in bytes
+		fields := strings.Fields(readString(filepath.Join(dir, "mm_stat")))
+		if len(fields) < 3 {
+			slog.With("path", dir).ErrorContext(ctx, "failed to read zram stats")
+			continue
+		}
+		values := make([]int64, 3)
+		for i := range values {
+			values[i], _ = strconv.ParseInt(fields[i], 10, 64)
+		}
+
+		result = append(result, model.ZramResult{
+			Device:             filepath.Base(dir),
+			DiskSize:           diskSize,
+			OriginalDataSize:   values[0],
+			CompressedDataSize: values[1],
+			MemoryUsed:         values[2],
+		})
+	}
+	return result
+}
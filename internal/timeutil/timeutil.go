@@ -0,0 +1,83 @@
+package timeutil
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTime     = errors.New("invalid time")
+	ErrInvalidDuration = errors.New("invalid duration")
+)
+
+var layouts = []string{
+	time.RFC3339Nano,
+	time.DateTime,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// ParseTime parses v as one of:
+//   - "now", or a duration relative to now such as "now-1h" or "-30m"
+//   - an RFC 3339 date, or "2006-01-02[ 15:04[:05]]" in local time
+//   - a unix timestamp in seconds
+func ParseTime(v string, now time.Time) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "now" {
+		return now, nil
+	}
+	if rel, ok := strings.CutPrefix(v, "now"); ok || strings.HasPrefix(v, "-") {
+		if !strings.HasPrefix(rel, "-") {
+			return time.Time{}, fmt.Errorf("%w: '%s'", ErrInvalidTime, v)
+		}
+		d, err := ParseDuration(strings.TrimPrefix(rel, "-"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: '%s'", ErrInvalidTime, v)
+		}
+		return now.Add(-d), nil
+	}
+
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l, v, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	secs, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: '%s' is neither a date, a relative time nor a unix timestamp", ErrInvalidTime, v)
+	}
+	return time.UnixMilli(int64(secs * 1000)), nil
+}
+
+// ParseDuration parses a Go duration, a number of days such as "7d" or
+// a number of seconds. Negative durations are rejected.
+func ParseDuration(v string) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+
+	var d time.Duration
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: '%s'", ErrInvalidDuration, v)
+		}
+		d = time.Duration(n * float64(24*time.Hour))
+	} else if parsed, err := time.ParseDuration(v); err == nil {
+		d = parsed
+	} else {
+		secs, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: '%s'", ErrInvalidDuration, v)
+		}
+		d = time.Duration(secs * float64(time.Second))
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("%w: '%s' is negative", ErrInvalidDuration, v)
+	}
+	return d, nil
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/NimbleMarkets/ntcharts/canvas/runes"
@@ -12,6 +15,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/eldius/rpi-system-monitor/internal/adapter"
+	"github.com/eldius/rpi-system-monitor/internal/model"
+	"github.com/eldius/rpi-system-monitor/internal/output"
+	"github.com/eldius/rpi-system-monitor/internal/persistence"
 	"github.com/eldius/rpi-system-monitor/internal/tui/helper"
 	zone "github.com/lrstanley/bubblezone"
 )
@@ -41,8 +47,48 @@ var (
 
 	labelStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("6")) // cyan
+
+	// Estilos das linhas de cada núcleo, a média usa graphLineStyle1
+	coreLineStyles = []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(lipgloss.Color("2")),  // green
+		lipgloss.NewStyle().Foreground(lipgloss.Color("5")),  // magenta
+		lipgloss.NewStyle().Foreground(lipgloss.Color("1")),  // red
+		lipgloss.NewStyle().Foreground(lipgloss.Color("7")),  // white
+		lipgloss.NewStyle().Foreground(lipgloss.Color("10")), // bright green
+		lipgloss.NewStyle().Foreground(lipgloss.Color("13")), // bright magenta
+		lipgloss.NewStyle().Foreground(lipgloss.Color("9")),  // bright red
+		lipgloss.NewStyle().Foreground(lipgloss.Color("15")), // bright white
+	}
+
+	// Estilos das abas de páginas
+	tabStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Padding(0, 1)
+
+	activeTabStyle = tabStyle.
+		Foreground(lipgloss.Color("205")).
+		Underline(true)
+
+	// Estilos das colunas que marcam reinicializações nos gráficos
+	rebootMarkStyle = lipgloss.NewStyle().
+		Background(lipgloss.Color("238")) // dark gray
+
+	unexpectedRebootMarkStyle = lipgloss.NewStyle().
+		Background(lipgloss.Color("52")) // dark red
+)
+
+// --- Páginas ---
+
+const (
+	pageOverview = iota
+	pageCPU
+	pageNetwork
+	pageProcesses
 )
 
+// pageTitles são os títulos das abas, na ordem em que são alternadas
+var pageTitles = []string{"Overview", "CPU", "Network", "Processes"}
+
 // --- Tipos de Mensagem ---
 
 // tickMsg é enviado a cada intervalo para atualizar os dados
@@ -53,17 +99,52 @@ type tickMsg time.Time
 type hostMetricsDisplayModel struct {
 	hostname string
 	ip       string
+	dbPath   string
 
 	// Gráficos do Ntcharts
 	cpuChart  *timeserieslinechart.Model
 	memChart  *timeserieslinechart.Model
 	tempChart *timeserieslinechart.Model
 
+	// Gráfico da divisão do tempo de CPU por modo
+	cpuTimesChart *timeserieslinechart.Model
+
+	// Gráficos de tráfego de rede, com uma linha por interface
+	netRxChart *timeserieslinechart.Model
+	netTxChart *timeserieslinechart.Model
+
+	// Interfaces exibidas nos gráficos de rede, na ordem em que foram
+	// encontradas
+	interfaces []string
+
+	// Processos que mais consomem CPU e memória na última medição, nil
+	// quando a sonda de processos está desabilitada
+	processes *model.ProcessesResult
+
+	// Largura das caixas, usada para cortar as linhas de comando
+	width int
+
+	// Tempo de atividade da última medição
+	uptime model.UptimeResult
+
+	// Eventos dentro do intervalo exibido nos gráficos
+	events []model.EventResult
+
+	// Página exibida
+	page int
+
 	zm *zone.Manager
 
 	// Dados brutos (simulados para o exemplo)
 	tickCount int
 
+	// Quantidade de núcleos exibidos no gráfico de CPU
+	cores int
+
+	// Sensores exibidos no gráfico de temperatura, na ordem em que
+	// foram encontrados
+	sensors []model.TemperatureSensorResult
+
 	ctx context.Context
 }
 
@@ -81,21 +162,37 @@ func initialModel(ctx context.Context) *hostMetricsDisplayModel {
 	cpuChart := timeserieslinechart.New(w, h)
 	memChart := timeserieslinechart.New(w, h)
 	tempChart := timeserieslinechart.New(w, h)
+	cpuTimesChart := timeserieslinechart.New(w, h)
+	netRxChart := timeserieslinechart.New(w, h)
+	netTxChart := timeserieslinechart.New(w, h)
 
 	zm := zone.New()
 
 	setupMetricChart(&cpuChart, zm)
 	setupMetricChart(&memChart, zm)
 	setupMetricChart(&tempChart, zm)
+	setupMetricChart(&cpuTimesChart, zm)
+	setupMetricChart(&netRxChart, zm)
+	setupMetricChart(&netTxChart, zm)
+
+	for i, mode := range model.CPUTimeModes {
+		cpuTimesChart.SetDataSetStyle(mode, coreLineStyle(i))
+	}
 
 	m := hostMetricsDisplayModel{
 		hostname:  host,
 		ip:        ip,
+		dbPath:    persistence.Path(),
 		cpuChart:  &cpuChart,
 		memChart:  &memChart,
 		tempChart: &tempChart,
 		ctx:       ctx,
 		zm:        zm,
+		width:     w,
+
+		cpuTimesChart: &cpuTimesChart,
+		netRxChart:    &netRxChart,
+		netTxChart:    &netTxChart,
 	}
 
 	return &m
@@ -130,6 +227,10 @@ func (m *hostMetricsDisplayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "q", tea.KeyCtrlC.String(), tea.KeyEscape.String():
 			return m, tea.Quit
+		case tea.KeyTab.String(), tea.KeyRight.String():
+			m.page = (m.page + 1) % len(pageTitles)
+		case tea.KeyShiftTab.String(), tea.KeyLeft.String():
+			m.page = (m.page + len(pageTitles) - 1) % len(pageTitles)
 		}
 
 	case tickMsg:
@@ -151,9 +252,60 @@ func (m *hostMetricsDisplayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.memChart.Push(memVal)
 		m.tempChart.Push(tempVal)
 
-		m.cpuChart.Draw()
+		for _, c := range measures.CPU.Cores {
+			name := coreDataSetName(c.Core)
+			if c.Core >= m.cores {
+				m.cores = c.Core + 1
+				m.cpuChart.SetDataSetStyle(name, coreLineStyle(c.Core))
+			}
+			m.cpuChart.PushDataSet(name, timeserieslinechart.TimePoint{Time: ts, Value: c.Usage})
+		}
+
+		for _, t := range measures.Temp.Sensors {
+			i := slices.IndexFunc(m.sensors, func(s model.TemperatureSensorResult) bool {
+				return s.Sensor == t.Sensor
+			})
+			if i < 0 {
+				i = len(m.sensors)
+				m.sensors = append(m.sensors, t)
+				m.tempChart.SetDataSetStyle(t.Sensor, coreLineStyle(i))
+			}
+			m.tempChart.PushDataSet(t.Sensor, timeserieslinechart.TimePoint{Time: ts, Value: t.Temperature})
+		}
+
+		for _, n := range measures.Network {
+			if !slices.Contains(m.interfaces, n.Interface) {
+				style := coreLineStyle(len(m.interfaces))
+				m.interfaces = append(m.interfaces, n.Interface)
+				m.netRxChart.SetDataSetStyle(n.Interface, style)
+				m.netTxChart.SetDataSetStyle(n.Interface, style)
+			}
+			m.netRxChart.PushDataSet(n.Interface, timeserieslinechart.TimePoint{Time: ts, Value: n.RxBytesPerSecond / 1024})
+			m.netTxChart.PushDataSet(n.Interface, timeserieslinechart.TimePoint{Time: ts, Value: n.TxBytesPerSecond / 1024})
+		}
+
+		m.processes = measures.Processes
+		m.uptime = measures.Uptime
+
+		for _, t := range measures.CPU.Times {
+			m.cpuTimesChart.PushDataSet(t.Mode, timeserieslinechart.TimePoint{Time: ts, Value: t.Percentage})
+		}
+
+		m.cpuChart.DrawAll()
 		m.memChart.Draw()
-		m.tempChart.Draw()
+		m.tempChart.DrawAll()
+		m.cpuTimesChart.DrawAll()
+		m.netRxChart.DrawAll()
+		m.netTxChart.DrawAll()
+
+		// As marcas precisam ser aplicadas depois de desenhar, que
+		// limpa o canvas
+		m.events, err = adapter.Events(m.ctx, time.Unix(int64(m.cpuChart.ViewMinX()), 0), ts)
+		if err != nil {
+			fmt.Println(err)
+			return m, tea.Quit
+		}
+		m.markEvents()
 
 		return m, tickCmd()
 	}
@@ -162,36 +314,52 @@ func (m *hostMetricsDisplayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *hostMetricsDisplayModel) View() string {
-	// Renderiza os gráficos para strings
-	cpuView := m.cpuChart.View()
-	memView := m.memChart.View()
-	tempView := m.tempChart.View()
-
 	// Constrói o cabeçalho
 	header := headerStyle.Render(
-		lipgloss.JoinVertical(lipgloss.Top, fmt.Sprintf("🖥️  HOST: %s  |  🌐 IP: %s", m.hostname, m.ip)),
+		lipgloss.JoinVertical(lipgloss.Top, fmt.Sprintf("🖥️  HOST: %s  |  🌐 IP: %s  |  💾 DB: %s  |  ⏱️  UP: %s", m.hostname, m.ip, m.dbPath, m.uptime.Uptime())),
 	) + "\n"
 
-	// Cria caixas com títulos para cada métrica
-	cpuBox := borderStyle.Render(
-		lipgloss.JoinVertical(lipgloss.Left, labelStyle.Render("CPU Usage (%)"), cpuView),
-	)
-	memBox := borderStyle.Render(
-		lipgloss.JoinVertical(lipgloss.Left, labelStyle.Render("Memory Usage (%)"), memView),
-	)
-	tempBox := borderStyle.Render(
-		lipgloss.JoinVertical(lipgloss.Left, labelStyle.Render("Temperature (°C)"), tempView),
-	)
+	// Cria caixas com títulos para cada métrica da página exibida
+	var boxes []string
+	switch m.page {
+	case pageCPU:
+		boxes = append(boxes,
+			chartBox("CPU Time (%) "+m.cpuTimesLegend(), m.cpuTimesChart),
+		)
+	case pageNetwork:
+		boxes = append(boxes,
+			chartBox("Network Receive (KiB/s) "+m.networkLegend(), m.netRxChart),
+			chartBox("Network Transmit (KiB/s) "+m.networkLegend(), m.netTxChart),
+		)
+	case pageProcesses:
+		if m.tickCount == 0 {
+			boxes = append(boxes, textBox("Processes", "Aguardando a primeira medição..."))
+			break
+		}
+		if m.processes == nil {
+			boxes = append(boxes, textBox("Processes", "Sonda de processos desabilitada (monitor.server.processes_probe.enabled)."))
+			break
+		}
+		boxes = append(boxes,
+			textBox("Top CPU", m.processList(m.processes.TopCPU())),
+			textBox("Top Memory", m.processList(m.processes.TopMemory())),
+		)
+	default:
+		boxes = append(boxes,
+			chartBox("CPU Usage (%) "+m.cpuLegend(), m.cpuChart),
+			chartBox("Memory Usage (%)", m.memChart),
+			chartBox("Temperature (°C) "+m.tempLegend(), m.tempChart),
+		)
+	}
 
 	// Layout final: Cabeçalho em cima, gráficos lado a lado (se couber) ou vertical
 	// Aqui usaremos vertical para garantir visualização simples
-	body := lipgloss.JoinVertical(lipgloss.Left,
-		header,
-		cpuBox,
-		memBox,
-		tempBox,
-		labelStyle.Render("\nPressione 'q' para sair."),
-	)
+	rows := append([]string{header, m.tabs()}, boxes...)
+	if legend := m.eventsLegend(); legend != "" {
+		rows = append(rows, legend)
+	}
+	rows = append(rows, labelStyle.Render("\nPressione 'tab' para trocar de página e 'q' para sair."))
+	body := lipgloss.JoinVertical(lipgloss.Left, rows...)
 
 	m.zm.Scan(body)
 
@@ -200,6 +368,147 @@ func (m *hostMetricsDisplayModel) View() string {
 
 // --- Utilitários ---
 
+// chartBox cria a caixa de um gráfico com o seu título
+func chartBox(title string, c *timeserieslinechart.Model) string {
+	return borderStyle.Render(
+		lipgloss.JoinVertical(lipgloss.Left, labelStyle.Render(title), c.View()),
+	)
+}
+
+// markEvents destaca nos gráficos as colunas das reinicializações,
+// explicando as lacunas nas séries
+func (m *hostMetricsDisplayModel) markEvents() {
+	charts := []*timeserieslinechart.Model{m.cpuChart, m.memChart, m.tempChart, m.cpuTimesChart, m.netRxChart, m.netTxChart}
+	for _, e := range m.events {
+		if e.Type != model.EventReboot {
+			continue
+		}
+		style := rebootMarkStyle
+		if e.Unexpected {
+			style = unexpectedRebootMarkStyle
+		}
+		for _, c := range charts {
+			c.SetColumnBackgroundStyle(e.Timestamp, style)
+		}
+	}
+}
+
+// eventsLegend lista as reinicializações marcadas nos gráficos
+func (m *hostMetricsDisplayModel) eventsLegend() string {
+	var legend []string
+	for _, e := range m.events {
+		if e.Type != model.EventReboot {
+			continue
+		}
+		style := rebootMarkStyle
+		if e.Unexpected {
+			style = unexpectedRebootMarkStyle
+		}
+		legend = append(legend, style.Render(" ")+" "+output.EventStr(e))
+	}
+	return strings.Join(legend, "  ")
+}
+
+// textBox cria a caixa de um texto com o seu título
+func textBox(title, text string) string {
+	return borderStyle.Render(
+		lipgloss.JoinVertical(lipgloss.Left, labelStyle.Render(title), text),
+	)
+}
+
+// processList monta a tabela de processos, cortando as linhas na
+// largura das caixas
+func (m *hostMetricsDisplayModel) processList(processes []model.ProcessResult) string {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PID\tUSER\tCPU%\tRSS\tTHREADS\tCOMMAND")
+	for _, p := range processes {
+		command := p.Cmdline
+		if command == "" {
+			command = p.Name
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%.1f\t%s\t%d\t%s\n", p.PID, p.User, p.CPUPercentage, model.ByteCountIEC(p.RSS), p.Threads, command)
+	}
+	_ = tw.Flush()
+
+	lines := strings.Split(strings.TrimRight(sb.String(), "\n"), "\n")
+	for i, l := range lines {
+		if r := []rune(l); m.width > 0 && len(r) > m.width {
+			lines[i] = string(r[:m.width-1]) + "…"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tabs lista as páginas, destacando a exibida
+func (m *hostMetricsDisplayModel) tabs() string {
+	var tabs []string
+	for i, title := range pageTitles {
+		if i == m.page {
+			tabs = append(tabs, activeTabStyle.Render(title))
+			continue
+		}
+		tabs = append(tabs, tabStyle.Render(title))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+}
+
+// cpuLegend identifica a cor da média e de cada núcleo no gráfico de CPU
+func (m *hostMetricsDisplayModel) cpuLegend() string {
+	legend := graphLineStyle1.Render("■ total")
+	for i := range m.cores {
+		legend += " " + coreLineStyle(i).Render(fmt.Sprintf("■ %s", coreDataSetName(i)))
+	}
+	return legend
+}
+
+// tempLegend identifica a cor de cada sensor no gráfico de temperatura
+func (m *hostMetricsDisplayModel) tempLegend() string {
+	if len(m.sensors) == 0 {
+		return ""
+	}
+	names := make(map[string]int, len(m.sensors))
+	for _, t := range m.sensors {
+		names[t.Name()]++
+	}
+	legend := []string{graphLineStyle1.Render("■ temperature")}
+	for i, t := range m.sensors {
+		// sensores com o mesmo nome (ex.: dois nvme "Composite") usam o identificador
+		name := t.Name()
+		if name == "" || names[name] > 1 {
+			name = t.Sensor
+		}
+		legend = append(legend, coreLineStyle(i).Render("■ "+name))
+	}
+	return strings.Join(legend, " ")
+}
+
+// networkLegend identifica a cor de cada interface nos gráficos de rede
+func (m *hostMetricsDisplayModel) networkLegend() string {
+	var legend []string
+	for i, name := range m.interfaces {
+		legend = append(legend, coreLineStyle(i).Render("■ "+name))
+	}
+	return strings.Join(legend, " ")
+}
+
+// cpuTimesLegend identifica a cor de cada modo no gráfico de tempo de CPU
+func (m *hostMetricsDisplayModel) cpuTimesLegend() string {
+	var legend []string
+	for i, mode := range model.CPUTimeModes {
+		legend = append(legend, coreLineStyle(i).Render("■ "+mode))
+	}
+	return strings.Join(legend, " ")
+}
+
+func coreDataSetName(core int) string {
+	return fmt.Sprintf("cpu%d", core)
+}
+
+func coreLineStyle(core int) lipgloss.Style {
+	return coreLineStyles[core%len(coreLineStyles)]
+}
+
 func tickCmd() tea.Cmd {
 	return tea.Tick(time.Second*5, func(t time.Time) tea.Msg {
 		return tickMsg(t)